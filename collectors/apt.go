@@ -0,0 +1,117 @@
+package collectors
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Package update state files. update-notifier refreshes updates-available after
+// every apt update, so reading it avoids running a package simulation per scrape.
+const (
+	aptUpdatesAvailableFile = "/var/lib/update-notifier/updates-available"
+	rebootRequiredFile      = "/run/reboot-required"
+	rebootRequiredPkgsFile  = "/run/reboot-required.pkgs"
+)
+
+var (
+	// "5 updates can be applied immediately." or "5 packages can be updated."
+	aptUpgradesRe = regexp.MustCompile(`(\d+) (?:updates?|packages?) can be (?:applied immediately|updated)`)
+	// "2 of these updates are standard security updates." or "2 updates are security updates."
+	aptSecurityRe = regexp.MustCompile(`(\d+) (?:of these updates (?:is|are)|updates? (?:is|are)) (?:a )?(?:standard )?security updates?`)
+)
+
+// AptCollector collects pending package updates and the reboot-required flag of
+// Debian and Ubuntu based systems.
+type AptCollector struct {
+	upgradesDesc       *prometheus.Desc
+	securityDesc       *prometheus.Desc
+	checkTimeDesc      *prometheus.Desc
+	rebootRequiredDesc *prometheus.Desc
+	rebootPkgsDesc     *prometheus.Desc
+}
+
+// NewAptCollector creates a new AptCollector.
+func NewAptCollector() *AptCollector {
+	return &AptCollector{
+		upgradesDesc: prometheus.NewDesc(
+			"apt_upgrades_pending",
+			"Number of package updates that can be applied",
+			nil, nil,
+		),
+		securityDesc: prometheus.NewDesc(
+			"apt_security_upgrades_pending",
+			"Number of pending package updates that are security updates",
+			nil, nil,
+		),
+		checkTimeDesc: prometheus.NewDesc(
+			"apt_updates_check_timestamp_seconds",
+			"Unix timestamp of the last pending update check by update-notifier",
+			nil, nil,
+		),
+		rebootRequiredDesc: prometheus.NewDesc(
+			"node_reboot_required",
+			"Whether an installed update requires a reboot (1 = reboot required)",
+			nil, nil,
+		),
+		rebootPkgsDesc: prometheus.NewDesc(
+			"node_reboot_required_packages",
+			"Number of packages whose update requires a reboot",
+			nil, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *AptCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upgradesDesc
+	ch <- c.securityDesc
+	ch <- c.checkTimeDesc
+	ch <- c.rebootRequiredDesc
+	ch <- c.rebootPkgsDesc
+}
+
+// Collect reads the update-notifier summary and the reboot-required flag files and
+// sends them to the channel. Update counts are omitted if update-notifier is not
+// installed.
+func (c *AptCollector) Collect(ch chan<- prometheus.Metric) {
+	if info, err := os.Stat(aptUpdatesAvailableFile); err == nil {
+		data, err := os.ReadFile(aptUpdatesAvailableFile)
+		if err == nil {
+			// The file is empty or omits the lines when there is nothing to report
+			text := string(data)
+			ch <- prometheus.MustNewConstMetric(c.upgradesDesc, prometheus.GaugeValue, matchCount(aptUpgradesRe, text))
+			ch <- prometheus.MustNewConstMetric(c.securityDesc, prometheus.GaugeValue, matchCount(aptSecurityRe, text))
+			ch <- prometheus.MustNewConstMetric(c.checkTimeDesc, prometheus.GaugeValue, float64(info.ModTime().Unix()))
+		}
+	}
+
+	required := 0.0
+	if _, err := os.Stat(rebootRequiredFile); err == nil {
+		required = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.rebootRequiredDesc, prometheus.GaugeValue, required)
+
+	pkgs := 0.0
+	if data, err := os.ReadFile(rebootRequiredPkgsFile); err == nil {
+		seen := make(map[string]bool)
+		for _, line := range strings.Fields(string(data)) {
+			seen[line] = true
+		}
+		pkgs = float64(len(seen))
+	}
+	ch <- prometheus.MustNewConstMetric(c.rebootPkgsDesc, prometheus.GaugeValue, pkgs)
+}
+
+// matchCount returns the number captured by re in text, or 0 if it does not match.
+func matchCount(re *regexp.Regexp, text string) float64 {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+	return v
+}
@@ -0,0 +1,283 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"io"
+	"log"
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Bandwidth probe parameters. The sink accepts a limited number of concurrent
+// streams, each for a limited time, so an open port cannot be used to tie up the
+// exporter.
+const (
+	bandwidthBufferSize     = 1 << 20
+	bandwidthSampleInterval = 100 * time.Millisecond
+	bandwidthSinkMaxStreams = 32
+	bandwidthSinkMaxTime    = time.Minute
+)
+
+// BandwidthProbeCollector periodically measures the TCP throughput to the
+// bandwidth probe sink of a peer exporter, e.g. over the ConnectX link between two
+// Sparks, and reports the results of the last run.
+type BandwidthProbeCollector struct {
+	successDesc    *prometheus.Desc
+	throughputDesc *prometheus.Desc
+	jitterDesc     *prometheus.Desc
+	lastRunDesc    *prometheus.Desc
+	bytesDesc      *prometheus.Desc
+
+	target   string
+	duration time.Duration
+	streams  int
+
+	mu         sync.Mutex
+	ran        bool
+	success    bool
+	throughput float64 // Gbit/s
+	jitter     float64 // Gbit/s
+	lastRun    time.Time
+	bytes      float64
+}
+
+// NewBandwidthProbeCollector creates a new BandwidthProbeCollector and starts a
+// probe every interval. Each probe sends data to target (host:port of a sink
+// started with ListenBandwidthProbe) over streams parallel TCP connections for
+// duration.
+func NewBandwidthProbeCollector(target string, interval, duration time.Duration, streams int) *BandwidthProbeCollector {
+	labels := []string{"target"}
+	c := &BandwidthProbeCollector{
+		successDesc: prometheus.NewDesc(
+			"bandwidth_probe_success",
+			"Whether the last bandwidth probe completed (1 = success, 0 = failure)",
+			labels, nil,
+		),
+		throughputDesc: prometheus.NewDesc(
+			"bandwidth_probe_throughput_gbps",
+			"TCP throughput received by the peer in the last bandwidth probe in Gbit/s",
+			labels, nil,
+		),
+		jitterDesc: prometheus.NewDesc(
+			"bandwidth_probe_jitter_gbps",
+			"Standard deviation of the throughput over 100 ms intervals of the last bandwidth probe in Gbit/s",
+			labels, nil,
+		),
+		lastRunDesc: prometheus.NewDesc(
+			"bandwidth_probe_last_run_timestamp_seconds",
+			"Unix timestamp of the end of the last bandwidth probe",
+			labels, nil,
+		),
+		bytesDesc: prometheus.NewDesc(
+			"bandwidth_probe_sent_bytes_total",
+			"Total bytes sent by bandwidth probes",
+			labels, nil,
+		),
+		target:   target,
+		duration: duration,
+		streams:  streams,
+	}
+
+	go func() {
+		for {
+			c.probe()
+			time.Sleep(interval)
+		}
+	}()
+	return c
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *BandwidthProbeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.successDesc
+	ch <- c.throughputDesc
+	ch <- c.jitterDesc
+	ch <- c.lastRunDesc
+	ch <- c.bytesDesc
+}
+
+// Collect sends the results of the last probe to the channel. Nothing is sent until
+// the first probe has completed.
+func (c *BandwidthProbeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.ran {
+		return
+	}
+	success := 0.0
+	if c.success {
+		success = 1
+		ch <- prometheus.MustNewConstMetric(c.throughputDesc, prometheus.GaugeValue, c.throughput, c.target)
+		ch <- prometheus.MustNewConstMetric(c.jitterDesc, prometheus.GaugeValue, c.jitter, c.target)
+	}
+	ch <- prometheus.MustNewConstMetric(c.successDesc, prometheus.GaugeValue, success, c.target)
+	ch <- prometheus.MustNewConstMetric(c.lastRunDesc, prometheus.GaugeValue, float64(c.lastRun.Unix()), c.target)
+	ch <- prometheus.MustNewConstMetric(c.bytesDesc, prometheus.CounterValue, c.bytes, c.target)
+}
+
+// probe runs one bandwidth test and records its results. The throughput is computed
+// from the byte counts the sink reports back, so data still buffered in the
+// sender's socket is not counted; the jitter is computed from the bytes written
+// per sample interval.
+func (c *BandwidthProbeCollector) probe() {
+	conns := make([]*net.TCPConn, 0, c.streams)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < c.streams; i++ {
+		conn, err := net.DialTimeout("tcp", c.target, 5*time.Second)
+		if err != nil {
+			log.Printf("bandwidth probe %s: %v", c.target, err)
+			c.record(false, 0, 0, 0)
+			return
+		}
+		conns = append(conns, conn.(*net.TCPConn))
+	}
+
+	var sent atomic.Uint64
+	start := time.Now()
+	deadline := start.Add(c.duration)
+	received := make([]uint64, len(conns))
+	errs := make([]error, len(conns))
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(i int, conn *net.TCPConn) {
+			defer wg.Done()
+			received[i], errs[i] = sendBandwidthStream(conn, deadline, &sent)
+		}(i, conn)
+	}
+
+	var samples []float64
+	last := uint64(0)
+	ticker := time.NewTicker(bandwidthSampleInterval)
+	for now := range ticker.C {
+		n := sent.Load()
+		samples = append(samples, float64(n-last)*8/bandwidthSampleInterval.Seconds()/1e9)
+		last = n
+		if !now.Before(deadline) {
+			break
+		}
+	}
+	ticker.Stop()
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var total uint64
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("bandwidth probe %s: %v", c.target, err)
+			c.record(false, 0, 0, float64(sent.Load()))
+			return
+		}
+		total += received[i]
+	}
+	c.record(true, float64(total)*8/elapsed.Seconds()/1e9, stddev(samples), float64(sent.Load()))
+}
+
+// record stores the results of a probe.
+func (c *BandwidthProbeCollector) record(success bool, throughput, jitter, sent float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ran = true
+	c.success = success
+	c.throughput = throughput
+	c.jitter = jitter
+	c.lastRun = time.Now()
+	c.bytes += sent
+}
+
+// sendBandwidthStream writes to conn until deadline, adding the bytes written to
+// sent, then half-closes the connection and returns the byte count received by
+// the sink.
+func sendBandwidthStream(conn *net.TCPConn, deadline time.Time, sent *atomic.Uint64) (uint64, error) {
+	buf := make([]byte, bandwidthBufferSize)
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return 0, err
+	}
+	for {
+		n, err := conn.Write(buf)
+		sent.Add(uint64(n))
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				break
+			}
+			return 0, err
+		}
+	}
+	if err := conn.CloseWrite(); err != nil {
+		return 0, err
+	}
+
+	var reply [8]byte
+	if err := conn.SetReadDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(reply[:]), nil
+}
+
+// ListenBandwidthProbe accepts bandwidth probe streams from a peer exporter on
+// addr, discarding the data and replying with the number of bytes received once
+// the sender half-closes the connection. It only returns on a listen error.
+func ListenBandwidthProbe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	slots := make(chan struct{}, bandwidthSinkMaxStreams)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Printf("bandwidth probe sink: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			conn.Close()
+			continue
+		}
+		go func() {
+			defer func() { <-slots }()
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(bandwidthSinkMaxTime))
+			n, err := io.Copy(io.Discard, conn)
+			if err != nil {
+				return
+			}
+			var reply [8]byte
+			binary.BigEndian.PutUint64(reply[:], uint64(n))
+			_, _ = conn.Write(reply[:])
+		}()
+	}
+}
+
+// stddev returns the population standard deviation of values.
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sq / float64(len(values)))
+}
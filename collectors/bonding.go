@@ -0,0 +1,226 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BondingCollector collects state of bonding and team interfaces and their member ports.
+type BondingCollector struct {
+	infoDesc          *prometheus.Desc
+	upDesc            *prometheus.Desc
+	slavesDesc        *prometheus.Desc
+	slavesUpDesc      *prometheus.Desc
+	slaveUpDesc       *prometheus.Desc
+	slaveActiveDesc   *prometheus.Desc
+	slaveFailuresDesc *prometheus.Desc
+}
+
+// NewBondingCollector creates a new BondingCollector.
+func NewBondingCollector() *BondingCollector {
+	slaveLabels := []string{"bond", "slave"}
+	return &BondingCollector{
+		infoDesc: prometheus.NewDesc(
+			"bond_info",
+			"Aggregated interface driver (bond or team) and mode (always 1)",
+			[]string{"bond", "driver", "mode"}, nil,
+		),
+		upDesc: prometheus.NewDesc(
+			"bond_up",
+			"Whether the aggregated interface has link (1 = up, 0 = down)",
+			[]string{"bond"}, nil,
+		),
+		slavesDesc: prometheus.NewDesc(
+			"bond_slaves",
+			"Number of member ports of the aggregated interface",
+			[]string{"bond"}, nil,
+		),
+		slavesUpDesc: prometheus.NewDesc(
+			"bond_slaves_up",
+			"Number of member ports of the aggregated interface with link up",
+			[]string{"bond"}, nil,
+		),
+		slaveUpDesc: prometheus.NewDesc(
+			"bond_slave_up",
+			"Whether the member port has link (1 = up, 0 = down)",
+			slaveLabels, nil,
+		),
+		slaveActiveDesc: prometheus.NewDesc(
+			"bond_slave_active",
+			"Whether the member port is actively passing traffic (1 = active, 0 = backup)",
+			slaveLabels, nil,
+		),
+		slaveFailuresDesc: prometheus.NewDesc(
+			"bond_slave_link_failures_total",
+			"Total link failures detected on the member port by the bonding driver",
+			slaveLabels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *BondingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.upDesc
+	ch <- c.slavesDesc
+	ch <- c.slavesUpDesc
+	ch <- c.slaveUpDesc
+	ch <- c.slaveActiveDesc
+	ch <- c.slaveFailuresDesc
+}
+
+// bondSlave holds the state of one member port.
+type bondSlave struct {
+	name     string
+	up       bool
+	active   bool
+	failures float64 // -1 if not reported
+}
+
+// Collect discovers bonding and team interfaces in /sys/class/net and sends
+// their state to the channel. Without aggregated interfaces no metrics are emitted.
+func (c *BondingCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing teamdctl when ctx is done.
+func (c *BondingCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	entries, err := os.ReadDir("/sys/class/net")
+	if err != nil {
+		return
+	}
+
+	var sock *ethtoolSocket
+	for _, e := range entries {
+		iface := e.Name()
+		ifaceDir := filepath.Join("/sys/class/net", iface)
+
+		var driver, mode string
+		var up bool
+		var slaves []bondSlave
+
+		if fileExists(filepath.Join(ifaceDir, "bonding")) {
+			driver = "bond"
+			mode, up, slaves = readBond(ifaceDir)
+		} else {
+			// Team devices have no sysfs directory of their own; identify them by driver name
+			if sock == nil {
+				if sock, err = openEthtoolSocket(); err != nil {
+					return
+				}
+				defer sock.Close()
+			}
+			if name, err := sock.driverName(iface); err != nil || name != "team" {
+				continue
+			}
+			driver = "team"
+			mode, up, slaves = readTeam(ctx, ifaceDir, iface)
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, iface, driver, mode)
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, boolToFloat(up), iface)
+		ch <- prometheus.MustNewConstMetric(c.slavesDesc, prometheus.GaugeValue, float64(len(slaves)), iface)
+
+		slavesUp := 0
+		for _, s := range slaves {
+			if s.up {
+				slavesUp++
+			}
+			ch <- prometheus.MustNewConstMetric(c.slaveUpDesc, prometheus.GaugeValue, boolToFloat(s.up), iface, s.name)
+			ch <- prometheus.MustNewConstMetric(c.slaveActiveDesc, prometheus.GaugeValue, boolToFloat(s.active), iface, s.name)
+			if s.failures >= 0 {
+				ch <- prometheus.MustNewConstMetric(c.slaveFailuresDesc, prometheus.CounterValue, s.failures, iface, s.name)
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(c.slavesUpDesc, prometheus.GaugeValue, float64(slavesUp), iface)
+	}
+}
+
+// readBond reads the mode, link state, and members of a bonding interface from
+// /sys/class/net/<bond>/bonding and /sys/class/net/<slave>/bonding_slave.
+func readBond(ifaceDir string) (mode string, up bool, slaves []bondSlave) {
+	bondingDir := filepath.Join(ifaceDir, "bonding")
+
+	// mode: "active-backup 1"
+	mode, _, _ = strings.Cut(readSysString(filepath.Join(bondingDir, "mode")), " ")
+	up = readSysString(filepath.Join(bondingDir, "mii_status")) == "up"
+	activeSlave := readSysString(filepath.Join(bondingDir, "active_slave"))
+
+	for _, name := range strings.Fields(readSysString(filepath.Join(bondingDir, "slaves"))) {
+		slaveDir := filepath.Join("/sys/class/net", name, "bonding_slave")
+		s := bondSlave{
+			name:     name,
+			up:       readSysString(filepath.Join(slaveDir, "mii_status")) == "up",
+			failures: float64(readSysUint64(filepath.Join(slaveDir, "link_failure_count"))),
+		}
+		if activeSlave != "" {
+			s.active = name == activeSlave
+		} else {
+			s.active = readSysString(filepath.Join(slaveDir, "state")) == "active"
+		}
+		slaves = append(slaves, s)
+	}
+	return mode, up, slaves
+}
+
+// teamdState is the subset of "teamdctl <team> state dump" output used by the collector.
+type teamdState struct {
+	Setup struct {
+		RunnerName string `json:"runner_name"`
+	} `json:"setup"`
+	Runner struct {
+		ActivePort string `json:"active_port"`
+	} `json:"runner"`
+	Ports map[string]struct {
+		Link struct {
+			Up bool `json:"up"`
+		} `json:"link"`
+	} `json:"ports"`
+}
+
+// readTeam reads the runner, link state, and ports of a team interface. Ports are
+// the interface's lower devices in sysfs; runner and active port come from teamdctl
+// if it is installed. Without an active-backup runner every port with link is active.
+func readTeam(ctx context.Context, ifaceDir, iface string) (mode string, up bool, slaves []bondSlave) {
+	up = readSysString(filepath.Join(ifaceDir, "carrier")) == "1"
+
+	var state teamdState
+	if out, err := exec.CommandContext(ctx, "teamdctl", iface, "state", "dump").Output(); err == nil {
+		_ = json.Unmarshal(out, &state)
+	}
+	mode = state.Setup.RunnerName
+
+	lowers, _ := filepath.Glob(filepath.Join(ifaceDir, "lower_*"))
+	sort.Strings(lowers)
+	for _, lower := range lowers {
+		name := strings.TrimPrefix(filepath.Base(lower), "lower_")
+		s := bondSlave{name: name, failures: -1}
+		if port, ok := state.Ports[name]; ok {
+			s.up = port.Link.Up
+		} else {
+			s.up = readSysString(filepath.Join("/sys/class/net", name, "carrier")) == "1"
+		}
+		if state.Runner.ActivePort != "" {
+			s.active = name == state.Runner.ActivePort
+		} else {
+			s.active = s.up
+		}
+		slaves = append(slaves, s)
+	}
+	return mode, up, slaves
+}
+
+// boolToFloat converts a boolean to 1 or 0.
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
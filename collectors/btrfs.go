@@ -0,0 +1,150 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// btrfsBlockGroupTypes are the allocation classes exposed under /sys/fs/btrfs/<uuid>/allocation.
+var btrfsBlockGroupTypes = []string{"data", "metadata", "system"}
+
+// BtrfsCollector collects allocation, reserve, and device error statistics
+// for mounted btrfs filesystems from /sys/fs/btrfs.
+type BtrfsCollector struct {
+	infoDesc         *prometheus.Desc
+	allocSizeDesc    *prometheus.Desc
+	allocUsedDesc    *prometheus.Desc
+	globalRsvDesc    *prometheus.Desc
+	globalRsvResDesc *prometheus.Desc
+	deviceErrorsDesc *prometheus.Desc
+}
+
+// NewBtrfsCollector creates a new BtrfsCollector.
+func NewBtrfsCollector() *BtrfsCollector {
+	allocLabels := []string{"uuid", "block_group_type", "profile"}
+	return &BtrfsCollector{
+		infoDesc: prometheus.NewDesc(
+			"btrfs_info",
+			"Btrfs filesystem information (always 1)",
+			[]string{"uuid", "label"}, nil,
+		),
+		allocSizeDesc: prometheus.NewDesc(
+			"btrfs_allocation_size_bytes",
+			"Logical size of the block groups allocated for a block group type and RAID profile in bytes",
+			allocLabels, nil,
+		),
+		allocUsedDesc: prometheus.NewDesc(
+			"btrfs_allocation_used_bytes",
+			"Logical bytes used within the block groups of a block group type and RAID profile",
+			allocLabels, nil,
+		),
+		globalRsvDesc: prometheus.NewDesc(
+			"btrfs_global_rsv_size_bytes",
+			"Size of the btrfs global metadata reserve in bytes",
+			[]string{"uuid"}, nil,
+		),
+		globalRsvResDesc: prometheus.NewDesc(
+			"btrfs_global_rsv_reserved_bytes",
+			"Bytes currently reserved from the btrfs global metadata reserve",
+			[]string{"uuid"}, nil,
+		),
+		deviceErrorsDesc: prometheus.NewDesc(
+			"btrfs_device_errors_total",
+			"Total number of btrfs device errors by type",
+			[]string{"uuid", "devid", "type"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *BtrfsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.allocSizeDesc
+	ch <- c.allocUsedDesc
+	ch <- c.globalRsvDesc
+	ch <- c.globalRsvResDesc
+	ch <- c.deviceErrorsDesc
+}
+
+// Collect reads statistics of every mounted btrfs filesystem and sends them to the channel.
+// If no btrfs filesystem is mounted, no metrics are emitted.
+func (c *BtrfsCollector) Collect(ch chan<- prometheus.Metric) {
+	fsDirs, err := filepath.Glob("/sys/fs/btrfs/*-*")
+	if err != nil {
+		return
+	}
+
+	for _, fsDir := range fsDirs {
+		uuid := filepath.Base(fsDir)
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1,
+			uuid, readSysString(filepath.Join(fsDir, "label")))
+
+		c.collectAllocation(ch, uuid, filepath.Join(fsDir, "allocation"))
+		c.collectDeviceErrors(ch, uuid, filepath.Join(fsDir, "devinfo"))
+	}
+}
+
+// collectAllocation reports per-profile block group allocation and the global reserve.
+func (c *BtrfsCollector) collectAllocation(ch chan<- prometheus.Metric, uuid, allocDir string) {
+	for _, bgType := range btrfsBlockGroupTypes {
+		entries, err := os.ReadDir(filepath.Join(allocDir, bgType))
+		if err != nil {
+			continue
+		}
+
+		// Each RAID profile in use (single, dup, raid1, ...) has its own subdirectory
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			profileDir := filepath.Join(allocDir, bgType, e.Name())
+			total := readSysUint64(filepath.Join(profileDir, "total_bytes"))
+			used := readSysUint64(filepath.Join(profileDir, "used_bytes"))
+
+			ch <- prometheus.MustNewConstMetric(c.allocSizeDesc, prometheus.GaugeValue, float64(total), uuid, bgType, e.Name())
+			ch <- prometheus.MustNewConstMetric(c.allocUsedDesc, prometheus.GaugeValue, float64(used), uuid, bgType, e.Name())
+		}
+	}
+
+	rsvSize := readSysUint64(filepath.Join(allocDir, "global_rsv_size"))
+	rsvReserved := readSysUint64(filepath.Join(allocDir, "global_rsv_reserved"))
+	ch <- prometheus.MustNewConstMetric(c.globalRsvDesc, prometheus.GaugeValue, float64(rsvSize), uuid)
+	ch <- prometheus.MustNewConstMetric(c.globalRsvResDesc, prometheus.GaugeValue, float64(rsvReserved), uuid)
+}
+
+// collectDeviceErrors reports the per-device error counters from devinfo/<devid>/error_stats
+// (kernel 5.14+).
+func (c *BtrfsCollector) collectDeviceErrors(ch chan<- prometheus.Metric, uuid, devinfoDir string) {
+	devices, err := os.ReadDir(devinfoDir)
+	if err != nil {
+		return
+	}
+
+	for _, d := range devices {
+		f, err := os.Open(filepath.Join(devinfoDir, d.Name(), "error_stats"))
+		if err != nil {
+			continue
+		}
+
+		// Format: "write_errs 0" per line
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 2 {
+				continue
+			}
+			v, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				continue
+			}
+			errType := strings.TrimSuffix(fields[0], "_errs")
+			ch <- prometheus.MustNewConstMetric(c.deviceErrorsDesc, prometheus.CounterValue, v, uuid, d.Name(), errType)
+		}
+		f.Close()
+	}
+}
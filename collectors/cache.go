@@ -0,0 +1,58 @@
+package collectors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// CachingGatherer gathers metrics in the background at a fixed interval and
+// serves the latest snapshot, so scrapes do not wait for the collectors and
+// several scrapers do not multiply their work.
+type CachingGatherer struct {
+	gatherer prometheus.Gatherer
+	ready    chan struct{}
+
+	mu       sync.Mutex
+	families []*dto.MetricFamily
+	err      error
+}
+
+// NewCachingGatherer creates a new CachingGatherer and starts gathering g every
+// interval.
+func NewCachingGatherer(g prometheus.Gatherer, interval time.Duration) *CachingGatherer {
+	c := &CachingGatherer{
+		gatherer: g,
+		ready:    make(chan struct{}),
+	}
+
+	go func() {
+		c.refresh()
+		close(c.ready)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.refresh()
+		}
+	}()
+	return c
+}
+
+// refresh gathers a new snapshot and replaces the cached one.
+func (c *CachingGatherer) refresh() {
+	families, err := c.gatherer.Gather()
+	c.mu.Lock()
+	c.families, c.err = families, err
+	c.mu.Unlock()
+}
+
+// Gather returns the latest snapshot and the error of the gathering that
+// produced it. Before the first snapshot is complete, it waits for it.
+func (c *CachingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	<-c.ready
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.families, c.err
+}
@@ -0,0 +1,151 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cgroupRoot is the mountpoint of the unified (v2) cgroup hierarchy.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupIOStatKeys maps io.stat keys to exported counter metrics.
+var cgroupIOStatKeys = []struct {
+	key, name, help string
+}{
+	{"rbytes", "cgroup_io_read_bytes_total", "Total bytes read from the device by the cgroup"},
+	{"wbytes", "cgroup_io_written_bytes_total", "Total bytes written to the device by the cgroup"},
+	{"rios", "cgroup_io_reads_total", "Total read operations issued to the device by the cgroup"},
+	{"wios", "cgroup_io_writes_total", "Total write operations issued to the device by the cgroup"},
+	{"dbytes", "cgroup_io_discarded_bytes_total", "Total bytes discarded on the device by the cgroup"},
+	{"dios", "cgroup_io_discards_total", "Total discard operations issued to the device by the cgroup"},
+}
+
+// CgroupIOCollector collects per-cgroup block I/O statistics from cgroup v2 io.stat files.
+type CgroupIOCollector struct {
+	maxDepth int
+	descs    map[string]*prometheus.Desc // keyed by io.stat key
+}
+
+// NewCgroupIOCollector creates a new CgroupIOCollector reporting cgroups up to
+// maxDepth levels below the root (1 = slices such as system.slice, 2 = their
+// services and container scopes, ...).
+func NewCgroupIOCollector(maxDepth int) *CgroupIOCollector {
+	descs := make(map[string]*prometheus.Desc, len(cgroupIOStatKeys))
+	for _, k := range cgroupIOStatKeys {
+		descs[k.key] = prometheus.NewDesc(k.name, k.help, []string{"cgroup", "device"}, nil)
+	}
+	return &CgroupIOCollector{
+		maxDepth: maxDepth,
+		descs:    descs,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *CgroupIOCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, k := range cgroupIOStatKeys {
+		ch <- c.descs[k.key]
+	}
+}
+
+// Collect reads io.stat of every cgroup within the configured depth and sends it to the channel.
+// If cgroup v2 is not mounted, no metrics are emitted.
+func (c *CgroupIOCollector) Collect(ch chan<- prometheus.Metric) {
+	devNames := make(map[string]string)
+
+	for _, cg := range listCgroups(c.maxDepth) {
+		stats, err := readCgroupIOStat(filepath.Join(cgroupRoot, cg, "io.stat"))
+		if err != nil {
+			continue
+		}
+
+		for majMin, values := range stats {
+			device, ok := devNames[majMin]
+			if !ok {
+				device = blockDeviceName(majMin)
+				devNames[majMin] = device
+			}
+
+			for key, v := range values {
+				desc, ok := c.descs[key]
+				if !ok {
+					continue
+				}
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v, cg, device)
+			}
+		}
+	}
+}
+
+// listCgroups returns the paths (relative to cgroupRoot, with a leading slash) of all
+// cgroups from depth 1 up to maxDepth.
+func listCgroups(maxDepth int) []string {
+	var cgroups []string
+	_ = filepath.WalkDir(cgroupRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(cgroupRoot, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+
+		depth := strings.Count(rel, string(filepath.Separator)) + 1
+		if depth > maxDepth {
+			return filepath.SkipDir
+		}
+		cgroups = append(cgroups, "/"+rel)
+		return nil
+	})
+	return cgroups
+}
+
+// readCgroupIOStat parses a cgroup v2 io.stat file into a map of
+// "major:minor" -> key -> value, e.g. "259:0" -> "rbytes" -> 1024.
+func readCgroupIOStat(path string) (map[string]map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := make(map[string]map[string]float64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: 259:0 rbytes=1024 wbytes=0 rios=1 wios=0 dbytes=0 dios=0
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		values := make(map[string]float64)
+		for _, kv := range fields[1:] {
+			key, valStr, ok := strings.Cut(kv, "=")
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(valStr, 64)
+			if err != nil {
+				continue
+			}
+			values[key] = v
+		}
+		stats[fields[0]] = values
+	}
+
+	return stats, scanner.Err()
+}
+
+// blockDeviceName resolves a "major:minor" device number to its kernel name
+// (e.g. nvme0n1), falling back to the number itself.
+func blockDeviceName(majMin string) string {
+	resolved, err := filepath.EvalSymlinks(filepath.Join("/sys/dev/block", majMin))
+	if err != nil {
+		return majMin
+	}
+	return filepath.Base(resolved)
+}
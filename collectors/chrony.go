@@ -0,0 +1,136 @@
+package collectors
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Field indexes of "chronyc -c tracking" output.
+const (
+	chronyTrackingRefName        = 1
+	chronyTrackingStratum        = 2
+	chronyTrackingCorrection     = 4
+	chronyTrackingLastOffset     = 5
+	chronyTrackingRMSOffset      = 6
+	chronyTrackingRootDelay      = 10
+	chronyTrackingRootDispersion = 11
+	chronyTrackingLeapStatus     = 13
+	chronyTrackingFields         = 14
+)
+
+// ChronyCollector collects the synchronization state of the chrony NTP daemon.
+type ChronyCollector struct {
+	stratumDesc        *prometheus.Desc
+	systemOffsetDesc   *prometheus.Desc
+	lastOffsetDesc     *prometheus.Desc
+	rmsOffsetDesc      *prometheus.Desc
+	rootDelayDesc      *prometheus.Desc
+	rootDispersionDesc *prometheus.Desc
+	syncedDesc         *prometheus.Desc
+
+	chronycPath string
+}
+
+// NewChronyCollector creates a new ChronyCollector that runs the chronyc binary at chronycPath.
+func NewChronyCollector(chronycPath string) *ChronyCollector {
+	return &ChronyCollector{
+		stratumDesc: prometheus.NewDesc(
+			"chrony_stratum",
+			"NTP stratum of the local clock (reference stratum + 1)",
+			[]string{"reference"}, nil,
+		),
+		systemOffsetDesc: prometheus.NewDesc(
+			"chrony_system_time_offset_seconds",
+			"Offset of the system clock from NTP time in seconds still being corrected (positive = system clock is behind)",
+			nil, nil,
+		),
+		lastOffsetDesc: prometheus.NewDesc(
+			"chrony_last_offset_seconds",
+			"Estimated local clock offset at the last clock update in seconds",
+			nil, nil,
+		),
+		rmsOffsetDesc: prometheus.NewDesc(
+			"chrony_rms_offset_seconds",
+			"Long-term average of the local clock offset in seconds",
+			nil, nil,
+		),
+		rootDelayDesc: prometheus.NewDesc(
+			"chrony_root_delay_seconds",
+			"Total network path delay to the stratum-1 source in seconds",
+			nil, nil,
+		),
+		rootDispersionDesc: prometheus.NewDesc(
+			"chrony_root_dispersion_seconds",
+			"Total dispersion accumulated back to the stratum-1 source in seconds",
+			nil, nil,
+		),
+		syncedDesc: prometheus.NewDesc(
+			"chrony_synchronized",
+			"Whether chrony considers the clock synchronized (leap status other than \"Not synchronised\")",
+			nil, nil,
+		),
+		chronycPath: chronycPath,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ChronyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.stratumDesc
+	ch <- c.systemOffsetDesc
+	ch <- c.lastOffsetDesc
+	ch <- c.rmsOffsetDesc
+	ch <- c.rootDelayDesc
+	ch <- c.rootDispersionDesc
+	ch <- c.syncedDesc
+}
+
+// Collect runs "chronyc -c tracking" and sends the tracking state to the channel.
+// If chronyc is not installed or chronyd is not running, no metrics are emitted.
+func (c *ChronyCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing chronyc when ctx is done.
+func (c *ChronyCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	out, err := exec.CommandContext(ctx, c.chronycPath, "-c", "-n", "tracking").Output()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.stratumDesc, fmt.Errorf("chronyc failed: %w", err))
+		return
+	}
+
+	fields, err := csv.NewReader(strings.NewReader(string(out))).Read()
+	if err != nil || len(fields) < chronyTrackingFields {
+		ch <- prometheus.NewInvalidMetric(c.stratumDesc, fmt.Errorf("chronyc: unexpected output format: %q", strings.TrimSpace(string(out))))
+		return
+	}
+
+	if v, err := strconv.ParseFloat(fields[chronyTrackingStratum], 64); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.stratumDesc, prometheus.GaugeValue, v, fields[chronyTrackingRefName])
+	}
+	for _, m := range []struct {
+		desc  *prometheus.Desc
+		field int
+	}{
+		{c.systemOffsetDesc, chronyTrackingCorrection},
+		{c.lastOffsetDesc, chronyTrackingLastOffset},
+		{c.rmsOffsetDesc, chronyTrackingRMSOffset},
+		{c.rootDelayDesc, chronyTrackingRootDelay},
+		{c.rootDispersionDesc, chronyTrackingRootDispersion},
+	} {
+		if v, err := strconv.ParseFloat(fields[m.field], 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(m.desc, prometheus.GaugeValue, v)
+		}
+	}
+
+	synced := 1.0
+	if fields[chronyTrackingLeapStatus] == "Not synchronised" {
+		synced = 0
+	}
+	ch <- prometheus.MustNewConstMetric(c.syncedDesc, prometheus.GaugeValue, synced)
+}
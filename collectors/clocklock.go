@@ -0,0 +1,108 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Frequency scaling domains: CPU cpufreq policies and devfreq devices (GPU, EMC,
+// and other accelerators on Tegra-style SoCs).
+const (
+	cpufreqPolicyGlob = "/sys/devices/system/cpu/cpufreq/policy*"
+	devfreqClassDir   = "/sys/class/devfreq"
+)
+
+// ClockLockCollector reports whether clocks are pinned to their maximum frequency
+// (as done by jetson_clocks or the performance governor) or scale dynamically.
+type ClockLockCollector struct {
+	lockedDesc   *prometheus.Desc
+	governorDesc *prometheus.Desc
+}
+
+// NewClockLockCollector creates a new ClockLockCollector.
+func NewClockLockCollector() *ClockLockCollector {
+	return &ClockLockCollector{
+		lockedDesc: prometheus.NewDesc(
+			"clock_locked",
+			"Whether the clock is pinned to its maximum frequency (1 = locked, 0 = dynamic scaling)",
+			[]string{"domain", "device"}, nil,
+		),
+		governorDesc: prometheus.NewDesc(
+			"clock_governor_info",
+			"Frequency scaling governor of the clock, always 1",
+			[]string{"domain", "device", "governor"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ClockLockCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lockedDesc
+	ch <- c.governorDesc
+}
+
+// Collect reads the cpufreq policies and devfreq devices and sends their lock state
+// to the channel. A clock counts as locked if its minimum frequency has been raised
+// to the hardware maximum or the performance governor is active.
+func (c *ClockLockCollector) Collect(ch chan<- prometheus.Metric) {
+	policies, _ := filepath.Glob(cpufreqPolicyGlob)
+	for _, dir := range policies {
+		governor := readSysString(filepath.Join(dir, "scaling_governor"))
+		minFreq, ok1 := readProcSysFloat(filepath.Join(dir, "scaling_min_freq"))
+		hwMax, ok2 := readProcSysFloat(filepath.Join(dir, "cpuinfo_max_freq"))
+		if !ok1 || !ok2 {
+			continue
+		}
+		c.send(ch, "cpu", filepath.Base(dir), governor, minFreq, hwMax)
+	}
+
+	devices, _ := os.ReadDir(devfreqClassDir)
+	for _, e := range devices {
+		dir := filepath.Join(devfreqClassDir, e.Name())
+		governor := readSysString(filepath.Join(dir, "governor"))
+		minFreq, ok := readProcSysFloat(filepath.Join(dir, "min_freq"))
+		if !ok {
+			continue
+		}
+		hwMax, ok := maxAvailableFrequency(filepath.Join(dir, "available_frequencies"))
+		if !ok {
+			// max_freq is the user-settable limit, only a fallback for the hardware maximum
+			if hwMax, ok = readProcSysFloat(filepath.Join(dir, "max_freq")); !ok {
+				continue
+			}
+		}
+		c.send(ch, "devfreq", e.Name(), governor, minFreq, hwMax)
+	}
+}
+
+// send emits the lock state and governor of one clock.
+func (c *ClockLockCollector) send(ch chan<- prometheus.Metric, domain, device, governor string, minFreq, hwMax float64) {
+	locked := 0.0
+	if minFreq >= hwMax || governor == "performance" {
+		locked = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.lockedDesc, prometheus.GaugeValue, locked, domain, device)
+	if governor != "" {
+		ch <- prometheus.MustNewConstMetric(c.governorDesc, prometheus.GaugeValue, 1, domain, device, governor)
+	}
+}
+
+// maxAvailableFrequency returns the highest frequency of a devfreq
+// available_frequencies list.
+func maxAvailableFrequency(path string) (float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	maxFreq, found := 0.0, false
+	for _, field := range strings.Fields(string(data)) {
+		if v, err := strconv.ParseFloat(field, 64); err == nil && v > maxFreq {
+			maxFreq, found = v, true
+		}
+	}
+	return maxFreq, found
+}
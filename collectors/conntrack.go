@@ -0,0 +1,131 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// conntrackStatCounters maps /proc/net/stat/nf_conntrack columns to exported counters.
+var conntrackStatCounters = []struct {
+	column, name, help string
+}{
+	{"invalid", "conntrack_invalid_total", "Total packets that could not be tracked"},
+	{"insert_failed", "conntrack_insert_failed_total", "Total connections that could not be inserted into the conntrack table"},
+	{"drop", "conntrack_drop_total", "Total packets dropped because a new conntrack entry could not be created"},
+	{"early_drop", "conntrack_early_drop_total", "Total conntrack entries evicted early to make room for new ones because the table was full"},
+}
+
+// ConntrackCollector collects netfilter connection tracking table usage.
+type ConntrackCollector struct {
+	entriesDesc *prometheus.Desc
+	limitDesc   *prometheus.Desc
+	statDescs   map[string]*prometheus.Desc // keyed by /proc/net/stat/nf_conntrack column
+}
+
+// NewConntrackCollector creates a new ConntrackCollector.
+func NewConntrackCollector() *ConntrackCollector {
+	statDescs := make(map[string]*prometheus.Desc, len(conntrackStatCounters))
+	for _, s := range conntrackStatCounters {
+		statDescs[s.column] = prometheus.NewDesc(s.name, s.help, nil, nil)
+	}
+
+	return &ConntrackCollector{
+		entriesDesc: prometheus.NewDesc(
+			"conntrack_entries",
+			"Number of entries currently in the conntrack table",
+			nil, nil,
+		),
+		limitDesc: prometheus.NewDesc(
+			"conntrack_entries_limit",
+			"Maximum number of entries in the conntrack table (net.netfilter.nf_conntrack_max)",
+			nil, nil,
+		),
+		statDescs: statDescs,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ConntrackCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entriesDesc
+	ch <- c.limitDesc
+	for _, s := range conntrackStatCounters {
+		ch <- c.statDescs[s.column]
+	}
+}
+
+// Collect reads conntrack table usage and sends it to the channel.
+// If the nf_conntrack module is not loaded, no metrics are emitted.
+func (c *ConntrackCollector) Collect(ch chan<- prometheus.Metric) {
+	count, err := strconv.ParseFloat(readSysString("/proc/sys/net/netfilter/nf_conntrack_count"), 64)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.GaugeValue, count)
+
+	if max, err := strconv.ParseFloat(readSysString("/proc/sys/net/netfilter/nf_conntrack_max"), 64); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.limitDesc, prometheus.GaugeValue, max)
+	}
+
+	rows, err := readProcNetStat("/proc/net/stat/nf_conntrack")
+	if err != nil {
+		return
+	}
+	for _, s := range conntrackStatCounters {
+		if v, ok := sumProcNetStat(rows, s.column); ok {
+			ch <- prometheus.MustNewConstMetric(c.statDescs[s.column], prometheus.CounterValue, v)
+		}
+	}
+}
+
+// readProcNetStat parses a /proc/net/stat/* file (nf_conntrack, arp_cache, ...), a
+// header line of column names followed by one line of hexadecimal values per CPU,
+// and returns one column -> value map per CPU.
+func readProcNetStat(path string) ([]map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return nil, scanner.Err()
+	}
+	header := strings.Fields(scanner.Text())
+
+	var rows []map[string]float64
+	for scanner.Scan() {
+		values := strings.Fields(scanner.Text())
+		if len(values) != len(header) {
+			continue
+		}
+		row := make(map[string]float64, len(header))
+		for i, column := range header {
+			v, err := strconv.ParseUint(values[i], 16, 64)
+			if err != nil {
+				continue
+			}
+			row[column] = float64(v)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+// sumProcNetStat returns the sum of column over all CPUs.
+func sumProcNetStat(rows []map[string]float64, column string) (float64, bool) {
+	var sum float64
+	found := false
+	for _, row := range rows {
+		if v, ok := row[column]; ok {
+			sum += v
+			found = true
+		}
+	}
+	return sum, found
+}
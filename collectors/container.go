@@ -0,0 +1,264 @@
+package collectors
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Container state directories used to resolve container IDs to names.
+const (
+	dockerContainersDir = "/var/lib/docker/containers"
+	podmanContainersDB  = "/var/lib/containers/storage/overlay-containers/containers.json"
+)
+
+// containerCgroupRe matches the cgroup of a container's processes and captures the
+// runtime and container ID: docker-<id>.scope and libpod-<id>.scope with the systemd
+// cgroup driver, docker/<id> with the cgroupfs driver.
+var containerCgroupRe = regexp.MustCompile(`(?:^|/)(docker|libpod)[-/]([0-9a-f]{64})(?:\.scope)?$`)
+
+// containerRuntimes maps the cgroup name prefix to the runtime label.
+var containerRuntimes = map[string]string{"docker": "docker", "libpod": "podman"}
+
+// containerCgroup is a running container found in the cgroup hierarchy.
+type containerCgroup struct {
+	path    string // absolute cgroup directory
+	id      string
+	runtime string
+}
+
+// ContainerCollector collects CPU, memory, and block I/O usage of Docker and Podman
+// containers from their cgroup v2 accounting files.
+type ContainerCollector struct {
+	cpuUsageDesc     *prometheus.Desc
+	cpuUserDesc      *prometheus.Desc
+	cpuSystemDesc    *prometheus.Desc
+	cpuThrottledDesc *prometheus.Desc
+	memoryUsageDesc  *prometheus.Desc
+	memoryLimitDesc  *prometheus.Desc
+	oomKillsDesc     *prometheus.Desc
+	ioReadDesc       *prometheus.Desc
+	ioWriteDesc      *prometheus.Desc
+}
+
+// NewContainerCollector creates a new ContainerCollector.
+func NewContainerCollector() *ContainerCollector {
+	labels := []string{"name", "id", "runtime"}
+	ioLabels := []string{"name", "id", "runtime", "device"}
+	return &ContainerCollector{
+		cpuUsageDesc: prometheus.NewDesc(
+			"container_cpu_usage_seconds_total",
+			"Total CPU time consumed by the container in seconds",
+			labels, nil,
+		),
+		cpuUserDesc: prometheus.NewDesc(
+			"container_cpu_user_seconds_total",
+			"Total CPU time consumed by the container in user mode in seconds",
+			labels, nil,
+		),
+		cpuSystemDesc: prometheus.NewDesc(
+			"container_cpu_system_seconds_total",
+			"Total CPU time consumed by the container in kernel mode in seconds",
+			labels, nil,
+		),
+		cpuThrottledDesc: prometheus.NewDesc(
+			"container_cpu_throttled_seconds_total",
+			"Total time the container was throttled by its CPU quota in seconds",
+			labels, nil,
+		),
+		memoryUsageDesc: prometheus.NewDesc(
+			"container_memory_usage_bytes",
+			"Memory currently charged to the container in bytes, including page cache",
+			labels, nil,
+		),
+		memoryLimitDesc: prometheus.NewDesc(
+			"container_memory_limit_bytes",
+			"Memory limit of the container in bytes (only for containers with a limit)",
+			labels, nil,
+		),
+		oomKillsDesc: prometheus.NewDesc(
+			"container_oom_kills_total",
+			"Total processes of the container killed by the OOM killer",
+			labels, nil,
+		),
+		ioReadDesc: prometheus.NewDesc(
+			"container_io_read_bytes_total",
+			"Total bytes read from the device by the container",
+			ioLabels, nil,
+		),
+		ioWriteDesc: prometheus.NewDesc(
+			"container_io_written_bytes_total",
+			"Total bytes written to the device by the container",
+			ioLabels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ContainerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuUsageDesc
+	ch <- c.cpuUserDesc
+	ch <- c.cpuSystemDesc
+	ch <- c.cpuThrottledDesc
+	ch <- c.memoryUsageDesc
+	ch <- c.memoryLimitDesc
+	ch <- c.oomKillsDesc
+	ch <- c.ioReadDesc
+	ch <- c.ioWriteDesc
+}
+
+// Collect finds container cgroups and sends their resource usage to the channel.
+// Containers whose name cannot be resolved are labelled with their short ID.
+func (c *ContainerCollector) Collect(ch chan<- prometheus.Metric) {
+	containers := listContainerCgroups()
+	if len(containers) == 0 {
+		return
+	}
+
+	podmanNames := readPodmanContainerNames()
+	devNames := make(map[string]string)
+
+	for _, ct := range containers {
+		name := ""
+		switch ct.runtime {
+		case "docker":
+			name = readDockerContainerName(ct.id)
+		case "podman":
+			name = podmanNames[ct.id]
+		}
+		shortID := ct.id[:12]
+		if name == "" {
+			name = shortID
+		}
+		labels := []string{name, shortID, ct.runtime}
+
+		if stats, err := readCgroupKeyedFile(filepath.Join(ct.path, "cpu.stat")); err == nil {
+			for key, desc := range map[string]*prometheus.Desc{
+				"usage_usec":     c.cpuUsageDesc,
+				"user_usec":      c.cpuUserDesc,
+				"system_usec":    c.cpuSystemDesc,
+				"throttled_usec": c.cpuThrottledDesc,
+			} {
+				if v, ok := stats[key]; ok {
+					ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v/1e6, labels...)
+				}
+			}
+		}
+
+		if v, ok := readProcSysFloat(filepath.Join(ct.path, "memory.current")); ok {
+			ch <- prometheus.MustNewConstMetric(c.memoryUsageDesc, prometheus.GaugeValue, v, labels...)
+		}
+		// memory.max holds "max" when unlimited, which does not parse
+		if v, ok := readProcSysFloat(filepath.Join(ct.path, "memory.max")); ok {
+			ch <- prometheus.MustNewConstMetric(c.memoryLimitDesc, prometheus.GaugeValue, v, labels...)
+		}
+		if events, err := readCgroupKeyedFile(filepath.Join(ct.path, "memory.events")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.oomKillsDesc, prometheus.CounterValue, events["oom_kill"], labels...)
+		}
+
+		if stats, err := readCgroupIOStat(filepath.Join(ct.path, "io.stat")); err == nil {
+			for majMin, values := range stats {
+				device, ok := devNames[majMin]
+				if !ok {
+					device = blockDeviceName(majMin)
+					devNames[majMin] = device
+				}
+				ioLabels := append(labels[:len(labels):len(labels)], device)
+				ch <- prometheus.MustNewConstMetric(c.ioReadDesc, prometheus.CounterValue, values["rbytes"], ioLabels...)
+				ch <- prometheus.MustNewConstMetric(c.ioWriteDesc, prometheus.CounterValue, values["wbytes"], ioLabels...)
+			}
+		}
+	}
+}
+
+// listContainerCgroups walks the cgroup hierarchy for Docker and Podman container
+// cgroups. Nested cgroups of a container (e.g. systemd running inside it) are not
+// descended into, as their usage is already included in the container's.
+func listContainerCgroups() []containerCgroup {
+	var containers []containerCgroup
+	_ = filepath.WalkDir(cgroupRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		m := containerCgroupRe.FindStringSubmatch(path)
+		if m == nil {
+			return nil
+		}
+		containers = append(containers, containerCgroup{
+			path:    path,
+			id:      m[2],
+			runtime: containerRuntimes[m[1]],
+		})
+		return filepath.SkipDir
+	})
+	return containers
+}
+
+// readDockerContainerName returns the name of a Docker container from its
+// config.v2.json, without the leading slash.
+func readDockerContainerName(id string) string {
+	data, err := os.ReadFile(filepath.Join(dockerContainersDir, id, "config.v2.json"))
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		Name string `json:"Name"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(config.Name, "/")
+}
+
+// readPodmanContainerNames returns the names of the root Podman containers keyed by ID.
+func readPodmanContainerNames() map[string]string {
+	names := make(map[string]string)
+	data, err := os.ReadFile(podmanContainersDB)
+	if err != nil {
+		return names
+	}
+	var containers []struct {
+		ID    string   `json:"id"`
+		Names []string `json:"names"`
+	}
+	if err := json.Unmarshal(data, &containers); err != nil {
+		return names
+	}
+	for _, ct := range containers {
+		if len(ct.Names) > 0 {
+			names[ct.ID] = ct.Names[0]
+		}
+	}
+	return names
+}
+
+// readCgroupKeyedFile parses a flat-keyed cgroup file such as cpu.stat or
+// memory.events ("<key> <value>" per line).
+func readCgroupKeyedFile(path string) (map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := make(map[string]float64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		stats[fields[0]] = v
+	}
+	return stats, scanner.Err()
+}
@@ -0,0 +1,74 @@
+package collectors
+
+import "sync"
+
+// wrapKey identifies one tracked counter.
+type wrapKey struct {
+	netns, iface, counter string
+}
+
+// wrapEntry is the tracked state of one counter.
+type wrapEntry struct {
+	ifindex int
+	last    uint64 // last raw value
+	offset  uint64 // added to raw values to account for observed wraps
+	seen    uint64 // generation of the last update
+}
+
+// wrapWindow bounds how far from the 32-bit limit a counter may be for a decrease
+// to count as a wrap: the previous value must lie within wrapWindow below 2^32 and
+// the new value below wrapWindow. This allows up to 2^31 increments between
+// scrapes.
+const wrapWindow = 1 << 30
+
+// wrapTracker turns counters that drivers keep in 32 bits into monotonic 64-bit
+// values by adding 2^32 whenever such a counter wraps around. Any other decrease,
+// such as a driver resetting its counters on a link flap, is a reset and leaves
+// the offset unchanged. A counter whose interface was recreated (new ifindex)
+// starts over.
+type wrapTracker struct {
+	mu         sync.Mutex
+	entries    map[wrapKey]*wrapEntry
+	generation uint64
+}
+
+// newWrapTracker creates an empty wrapTracker.
+func newWrapTracker() *wrapTracker {
+	return &wrapTracker{entries: make(map[wrapKey]*wrapEntry)}
+}
+
+// begin starts a collection cycle. Counters not adjusted until the matching end
+// call are forgotten.
+func (t *wrapTracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.generation++
+}
+
+// end forgets counters of interfaces that disappeared during the last cycle.
+func (t *wrapTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, e := range t.entries {
+		if e.seen != t.generation {
+			delete(t.entries, key)
+		}
+	}
+}
+
+// adjust records the raw value v of a counter and returns it corrected for wraps.
+func (t *wrapTracker) adjust(key wrapKey, ifindex int, v uint64) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.entries[key]
+	if !ok || e.ifindex != ifindex {
+		e = &wrapEntry{ifindex: ifindex}
+		t.entries[key] = e
+	} else if v < e.last && e.last <= 0xffffffff && e.last > 0xffffffff-wrapWindow && v < wrapWindow {
+		e.offset += 1 << 32
+	}
+	e.last = v
+	e.seen = t.generation
+	return v + e.offset
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -125,25 +126,24 @@ func (c *CPUCollector) readCPUUsage() (float64, bool) {
 }
 
 // readCPUTemperature reads CPU temperature from thermal zones.
-// It looks for a zone whose type contains "cpu" or "soc"; falls back to zone 0.
+// It looks for the first zone whose type contains "cpu" or "soc"; falls back to the
+// lowest-numbered zone.
 func readCPUTemperature() (float64, bool) {
-	// Search for a CPU/SoC thermal zone
-	for i := 0; i < 10; i++ {
-		typePath := fmt.Sprintf("/sys/class/thermal/thermal_zone%d/type", i)
-		typeBytes, err := os.ReadFile(typePath)
-		if err != nil {
-			continue
-		}
+	zones := listThermalZones()
+	if len(zones) == 0 {
+		return 0, false
+	}
 
-		zoneType := strings.ToLower(strings.TrimSpace(string(typeBytes)))
+	// Search for a CPU/SoC thermal zone
+	for _, z := range zones {
+		zoneType := strings.ToLower(z.typ)
 		if strings.Contains(zoneType, "cpu") || strings.Contains(zoneType, "soc") {
-			tempPath := fmt.Sprintf("/sys/class/thermal/thermal_zone%d/temp", i)
-			return readThermalTemp(tempPath)
+			return readThermalTemp(filepath.Join(z.dir, "temp"))
 		}
 	}
 
-	// Fallback: thermal_zone0
-	return readThermalTemp("/sys/class/thermal/thermal_zone0/temp")
+	// Fallback: first zone
+	return readThermalTemp(filepath.Join(zones[0].dir, "temp"))
 }
 
 // readThermalTemp reads a thermal zone temp file (millidegrees) and returns Celsius.
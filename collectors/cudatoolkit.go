@@ -0,0 +1,94 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CUDA toolkit locations. Toolkits install side by side under /usr/local/cuda-X.Y;
+// /usr/local/cuda points to the default one, on Ubuntu through the cuda
+// alternative managed by update-alternatives.
+const (
+	cudaToolkitGlob       = "/usr/local/cuda-*"
+	cudaToolkitLink       = "/usr/local/cuda"
+	cudaAlternativeStatus = "/var/lib/dpkg/alternatives/cuda"
+)
+
+// CUDAToolkitCollector exports the installed CUDA toolkits and which of them is
+// selected as the default.
+type CUDAToolkitCollector struct {
+	installedDesc *prometheus.Desc
+	activeDesc    *prometheus.Desc
+}
+
+// NewCUDAToolkitCollector creates a new CUDAToolkitCollector.
+func NewCUDAToolkitCollector() *CUDAToolkitCollector {
+	return &CUDAToolkitCollector{
+		installedDesc: prometheus.NewDesc(
+			"cuda_toolkit_installed_info",
+			"Installed CUDA toolkit, always 1",
+			[]string{"path", "version"}, nil,
+		),
+		activeDesc: prometheus.NewDesc(
+			"cuda_toolkit_active_info",
+			"CUDA toolkit /usr/local/cuda points to, always 1 (mode = alternatives selection mode: auto, manual, or empty if not managed by update-alternatives)",
+			[]string{"path", "version", "mode"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *CUDAToolkitCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.installedDesc
+	ch <- c.activeDesc
+}
+
+// Collect lists the toolkit directories and resolves /usr/local/cuda, sending the
+// info metrics to the channel. Symlinks such as /usr/local/cuda-13, which
+// update-alternatives points to the newest minor release, are not counted as
+// separate installations.
+func (c *CUDAToolkitCollector) Collect(ch chan<- prometheus.Metric) {
+	dirs, _ := filepath.Glob(cudaToolkitGlob)
+	for _, dir := range dirs {
+		info, err := os.Lstat(dir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.installedDesc, prometheus.GaugeValue, 1, dir, cudaToolkitVersion(dir))
+	}
+
+	active, err := filepath.EvalSymlinks(cudaToolkitLink)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.activeDesc, prometheus.GaugeValue, 1, active, cudaToolkitVersion(active), readAlternativeMode(cudaAlternativeStatus))
+}
+
+// cudaToolkitVersion returns the version of the toolkit in dir from its
+// version.json, falling back to the version in the directory name.
+func cudaToolkitVersion(dir string) string {
+	if version := readCUDAVersion(filepath.Join(dir, "version.json")); version != "" {
+		return version
+	}
+	return strings.TrimPrefix(filepath.Base(dir), "cuda-")
+}
+
+// readAlternativeMode returns the selection mode (auto or manual) from the first
+// line of a dpkg alternatives status file, or an empty string if it is missing.
+func readAlternativeMode(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return ""
+	}
+	return strings.TrimSpace(scanner.Text())
+}
@@ -0,0 +1,388 @@
+package collectors
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dbusSystemBusSocket is the address of the D-Bus system bus.
+const dbusSystemBusSocket = "/run/dbus/system_bus_socket"
+
+// D-Bus message types and header field codes from the D-Bus specification.
+const (
+	dbusTypeMethodCall   = 1
+	dbusTypeMethodReturn = 2
+	dbusTypeError        = 3
+
+	dbusFieldPath        = 1
+	dbusFieldInterface   = 2
+	dbusFieldMember      = 3
+	dbusFieldErrorName   = 4
+	dbusFieldReplySerial = 5
+	dbusFieldDestination = 6
+	dbusFieldSignature   = 8
+
+	dbusHeaderBytes    = 16
+	dbusMaxMessageSize = 1 << 20 // far above any reply requested here
+)
+
+// dbusConn is a minimal D-Bus client connection that supports method calls with
+// string and string array arguments, which is all the systemd collector needs.
+type dbusConn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	serial uint32
+}
+
+// dialSystemBus connects and authenticates to the system bus. All I/O on the
+// connection, including later calls, must complete before the deadline.
+func dialSystemBus(deadline time.Time) (*dbusConn, error) {
+	conn, err := net.DialTimeout("unix", dbusSystemBusSocket, time.Until(deadline))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(deadline)
+
+	c := &dbusConn{conn: conn, r: bufio.NewReader(conn)}
+	if err := c.auth(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := c.call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connection.
+func (c *dbusConn) Close() error {
+	return c.conn.Close()
+}
+
+// auth performs SASL EXTERNAL authentication with the caller's uid.
+func (c *dbusConn) auth() error {
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := io.WriteString(c.conn, "\x00AUTH EXTERNAL "+uid+"\r\n"); err != nil {
+		return err
+	}
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return fmt.Errorf("dbus authentication rejected: %s", strings.TrimSpace(line))
+	}
+	_, err = io.WriteString(c.conn, "BEGIN\r\n")
+	return err
+}
+
+// call invokes a method and returns the reply. Arguments must be of type string
+// or []string. Messages other than the reply, such as signals, are discarded.
+func (c *dbusConn) call(dest, path, iface, member string, args ...any) (*dbusMessage, error) {
+	c.serial++
+
+	var body dbusEncoder
+	var sig strings.Builder
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			body.string(v)
+			sig.WriteString("s")
+		case []string:
+			body.stringArray(v)
+			sig.WriteString("as")
+		default:
+			return nil, fmt.Errorf("dbus: unsupported argument type %T", arg)
+		}
+	}
+
+	var msg dbusEncoder
+	msg.buf = append(msg.buf, 'l', dbusTypeMethodCall, 0, 1)
+	msg.uint32(uint32(len(body.buf)))
+	msg.uint32(c.serial)
+	msg.uint32(0) // header field array length, patched below
+	msg.field(dbusFieldPath, "o", path)
+	msg.field(dbusFieldInterface, "s", iface)
+	msg.field(dbusFieldMember, "s", member)
+	msg.field(dbusFieldDestination, "s", dest)
+	if len(args) > 0 {
+		msg.field(dbusFieldSignature, "g", sig.String())
+	}
+	binary.LittleEndian.PutUint32(msg.buf[12:], uint32(len(msg.buf)-dbusHeaderBytes))
+	msg.align(8)
+	msg.buf = append(msg.buf, body.buf...)
+
+	if _, err := c.conn.Write(msg.buf); err != nil {
+		return nil, err
+	}
+
+	for {
+		reply, err := c.readMessage()
+		if err != nil {
+			return nil, err
+		}
+		if reply.replySerial != c.serial {
+			continue
+		}
+		switch reply.typ {
+		case dbusTypeMethodReturn:
+			return reply, nil
+		case dbusTypeError:
+			text := reply.errorName
+			if strings.HasPrefix(reply.signature, "s") {
+				d := reply.decoder()
+				if s := d.string(); d.err == nil {
+					text += ": " + s
+				}
+			}
+			return nil, errors.New(text)
+		}
+	}
+}
+
+// getStringProperty reads a string-typed property through org.freedesktop.DBus.Properties.
+func (c *dbusConn) getStringProperty(dest, path, iface, name string) (string, error) {
+	reply, err := c.call(dest, path, "org.freedesktop.DBus.Properties", "Get", iface, name)
+	if err != nil {
+		return "", err
+	}
+	if reply.signature != "v" {
+		return "", fmt.Errorf("dbus: unexpected reply signature %q", reply.signature)
+	}
+	d := reply.decoder()
+	if vsig := d.signature(); vsig != "s" {
+		return "", fmt.Errorf("dbus: property %s has type %q, want string", name, vsig)
+	}
+	s := d.string()
+	return s, d.err
+}
+
+// getUint64Property reads a uint64-typed property through org.freedesktop.DBus.Properties.
+func (c *dbusConn) getUint64Property(dest, path, iface, name string) (uint64, error) {
+	reply, err := c.call(dest, path, "org.freedesktop.DBus.Properties", "Get", iface, name)
+	if err != nil {
+		return 0, err
+	}
+	if reply.signature != "v" {
+		return 0, fmt.Errorf("dbus: unexpected reply signature %q", reply.signature)
+	}
+	d := reply.decoder()
+	if vsig := d.signature(); vsig != "t" {
+		return 0, fmt.Errorf("dbus: property %s has type %q, want uint64", name, vsig)
+	}
+	v := d.uint64()
+	return v, d.err
+}
+
+// dbusMessage is the subset of a received message used by call.
+type dbusMessage struct {
+	typ         byte
+	order       binary.ByteOrder
+	replySerial uint32
+	errorName   string
+	signature   string
+	body        []byte
+}
+
+// decoder returns a decoder positioned at the start of the message body.
+func (m *dbusMessage) decoder() *dbusDecoder {
+	return &dbusDecoder{buf: m.body, order: m.order}
+}
+
+// readMessage reads and decodes the next message from the connection.
+func (c *dbusConn) readMessage() (*dbusMessage, error) {
+	head := make([]byte, dbusHeaderBytes)
+	if _, err := io.ReadFull(c.r, head); err != nil {
+		return nil, err
+	}
+
+	m := &dbusMessage{typ: head[1]}
+	switch head[0] {
+	case 'l':
+		m.order = binary.LittleEndian
+	case 'B':
+		m.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("dbus: invalid endianness marker %q", head[0])
+	}
+
+	bodyLen := int(m.order.Uint32(head[4:]))
+	fieldsLen := int(m.order.Uint32(head[12:]))
+	fieldsEnd := dbusHeaderBytes + fieldsLen
+	headerLen := (fieldsEnd + 7) &^ 7
+	if bodyLen > dbusMaxMessageSize || fieldsLen > dbusMaxMessageSize {
+		return nil, errors.New("dbus: message too large")
+	}
+
+	buf := make([]byte, headerLen+bodyLen)
+	copy(buf, head)
+	if _, err := io.ReadFull(c.r, buf[dbusHeaderBytes:]); err != nil {
+		return nil, err
+	}
+
+	// Header fields are an array of (byte, variant) structs; alignment is relative
+	// to the start of the message.
+	d := dbusDecoder{buf: buf[:fieldsEnd], pos: dbusHeaderBytes, order: m.order}
+	for d.err == nil && d.pos < fieldsEnd {
+		d.align(8)
+		code := d.byte()
+		switch sig := d.signature(); sig {
+		case "u":
+			v := d.uint32()
+			if code == dbusFieldReplySerial {
+				m.replySerial = v
+			}
+		case "s", "o":
+			v := d.string()
+			if code == dbusFieldErrorName {
+				m.errorName = v
+			}
+		case "g":
+			v := d.signature()
+			if code == dbusFieldSignature {
+				m.signature = v
+			}
+		default:
+			return nil, fmt.Errorf("dbus: unsupported header field type %q", sig)
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	m.body = buf[headerLen:]
+	return m, nil
+}
+
+// dbusEncoder marshals values in little-endian D-Bus wire format.
+type dbusEncoder struct {
+	buf []byte
+}
+
+func (e *dbusEncoder) align(n int) {
+	for len(e.buf)%n != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *dbusEncoder) uint32(v uint32) {
+	e.align(4)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *dbusEncoder) string(s string) {
+	e.uint32(uint32(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+func (e *dbusEncoder) stringArray(a []string) {
+	e.uint32(0) // array length in bytes, patched below
+	lenPos := len(e.buf) - 4
+	start := len(e.buf)
+	for _, s := range a {
+		e.string(s)
+	}
+	binary.LittleEndian.PutUint32(e.buf[lenPos:], uint32(len(e.buf)-start))
+}
+
+func (e *dbusEncoder) signature(s string) {
+	e.buf = append(e.buf, byte(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+// field appends a header field struct holding a string-like variant.
+func (e *dbusEncoder) field(code byte, sig, value string) {
+	e.align(8)
+	e.buf = append(e.buf, code)
+	e.signature(sig)
+	if sig == "g" {
+		e.signature(value)
+	} else {
+		e.string(value)
+	}
+}
+
+// dbusDecoder unmarshals D-Bus wire format values. The first out-of-bounds read
+// sets err and all further reads return zero values.
+type dbusDecoder struct {
+	buf   []byte
+	pos   int
+	order binary.ByteOrder
+	err   error
+}
+
+func (d *dbusDecoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.pos+n > len(d.buf) {
+		d.err = errors.New("dbus: truncated message")
+		return nil
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *dbusDecoder) align(n int) {
+	if pad := (n - d.pos%n) % n; pad > 0 {
+		d.take(pad)
+	}
+}
+
+func (d *dbusDecoder) byte() byte {
+	if b := d.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *dbusDecoder) uint32() uint32 {
+	d.align(4)
+	if b := d.take(4); b != nil {
+		return d.order.Uint32(b)
+	}
+	return 0
+}
+
+func (d *dbusDecoder) uint64() uint64 {
+	d.align(8)
+	if b := d.take(8); b != nil {
+		return d.order.Uint64(b)
+	}
+	return 0
+}
+
+func (d *dbusDecoder) string() string {
+	n := d.uint32()
+	if n > dbusMaxMessageSize {
+		d.err = errors.New("dbus: string too long")
+		return ""
+	}
+	s := d.take(int(n) + 1) // trailing NUL
+	if s == nil {
+		return ""
+	}
+	return string(s[:n])
+}
+
+func (d *dbusDecoder) signature() string {
+	n := int(d.byte())
+	s := d.take(n + 1) // trailing NUL
+	if s == nil {
+		return ""
+	}
+	return string(s[:n])
+}
@@ -0,0 +1,174 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// lvmVGReport is the subset of `vgs --reportformat json` output used by the collector.
+type lvmVGReport struct {
+	Report []struct {
+		VG []struct {
+			Name string `json:"vg_name"`
+			Size string `json:"vg_size"`
+			Free string `json:"vg_free"`
+		} `json:"vg"`
+	} `json:"report"`
+}
+
+// DeviceMapperCollector collects I/O statistics for device-mapper devices and
+// LVM volume group capacity.
+type DeviceMapperCollector struct {
+	vgsPath string
+
+	infoDesc   *prometheus.Desc
+	fields     []diskstatField
+	vgSizeDesc *prometheus.Desc
+	vgFreeDesc *prometheus.Desc
+
+	mu          sync.Mutex
+	loggedVGErr bool
+}
+
+// NewDeviceMapperCollector creates a new DeviceMapperCollector that runs the LVM
+// vgs binary at vgsPath.
+func NewDeviceMapperCollector(vgsPath string) *DeviceMapperCollector {
+	labels := []string{"device", "name"}
+	return &DeviceMapperCollector{
+		vgsPath: vgsPath,
+		infoDesc: prometheus.NewDesc(
+			"devicemapper_device_info",
+			"Device-mapper device information (always 1)",
+			[]string{"device", "name", "uuid"}, nil,
+		),
+		fields: []diskstatField{
+			newDiskstatField(3, "devicemapper_reads_completed_total",
+				"Total number of completed reads on the device-mapper device",
+				labels, prometheus.CounterValue, 1),
+			newDiskstatField(5, "devicemapper_read_bytes_total",
+				"Total number of bytes read from the device-mapper device",
+				labels, prometheus.CounterValue, diskSectorSize),
+			newDiskstatField(7, "devicemapper_writes_completed_total",
+				"Total number of completed writes on the device-mapper device",
+				labels, prometheus.CounterValue, 1),
+			newDiskstatField(9, "devicemapper_written_bytes_total",
+				"Total number of bytes written to the device-mapper device",
+				labels, prometheus.CounterValue, diskSectorSize),
+			newDiskstatField(11, "devicemapper_io_now",
+				"Number of I/O requests currently in flight on the device-mapper device",
+				labels, prometheus.GaugeValue, 1),
+			newDiskstatField(12, "devicemapper_io_time_seconds_total",
+				"Total time the device-mapper device had I/O in flight in seconds",
+				labels, prometheus.CounterValue, 0.001),
+		},
+		vgSizeDesc: prometheus.NewDesc(
+			"lvm_vg_size_bytes",
+			"Size of the LVM volume group in bytes",
+			[]string{"vg"}, nil,
+		),
+		vgFreeDesc: prometheus.NewDesc(
+			"lvm_vg_free_bytes",
+			"Unallocated space in the LVM volume group in bytes",
+			[]string{"vg"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *DeviceMapperCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	for _, f := range c.fields {
+		ch <- f.desc
+	}
+	ch <- c.vgSizeDesc
+	ch <- c.vgFreeDesc
+}
+
+// Collect sends device-mapper I/O statistics and LVM volume group capacity to the channel.
+func (c *DeviceMapperCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing vgs when ctx is done.
+func (c *DeviceMapperCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	c.collectDeviceMapper(ch)
+	c.collectVolumeGroups(ctx, ch)
+}
+
+// collectDeviceMapper reports /proc/diskstats for dm-* devices, labelled with
+// their device-mapper name (e.g. ubuntu--vg-ubuntu--lv) from /sys/block/dm-*/dm.
+func (c *DeviceMapperCollector) collectDeviceMapper(ch chan<- prometheus.Metric) {
+	stats, err := readDiskstats()
+	if err != nil {
+		return
+	}
+
+	for _, fields := range stats {
+		device := fields[2]
+		if !strings.HasPrefix(device, "dm-") {
+			continue
+		}
+
+		dmDir := filepath.Join("/sys/block", device, "dm")
+		name := readSysString(filepath.Join(dmDir, "name"))
+		uuid := readSysString(filepath.Join(dmDir, "uuid"))
+
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, device, name, uuid)
+		for _, f := range c.fields {
+			f.collect(ch, fields, device, name)
+		}
+	}
+}
+
+// collectVolumeGroups runs vgs and reports size and free space of each LVM volume group.
+// LVM is optional, so if vgs is missing or fails only the volume group metrics are
+// skipped; the first failure is logged.
+func (c *DeviceMapperCollector) collectVolumeGroups(ctx context.Context, ch chan<- prometheus.Metric) {
+	out, err := exec.CommandContext(
+		ctx,
+		c.vgsPath,
+		"--reportformat", "json",
+		"--units", "b", "--nosuffix",
+		"-o", "vg_name,vg_size,vg_free",
+	).Output()
+	if err != nil {
+		c.logVGError(fmt.Errorf("vgs failed: %w", err))
+		return
+	}
+
+	var report lvmVGReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		c.logVGError(fmt.Errorf("vgs: unexpected output format: %w", err))
+		return
+	}
+
+	for _, r := range report.Report {
+		for _, vg := range r.VG {
+			if size, err := strconv.ParseFloat(vg.Size, 64); err == nil {
+				ch <- prometheus.MustNewConstMetric(c.vgSizeDesc, prometheus.GaugeValue, size, vg.Name)
+			}
+			if free, err := strconv.ParseFloat(vg.Free, 64); err == nil {
+				ch <- prometheus.MustNewConstMetric(c.vgFreeDesc, prometheus.GaugeValue, free, vg.Name)
+			}
+		}
+	}
+}
+
+// logVGError logs the first volume group failure.
+func (c *DeviceMapperCollector) logVGError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loggedVGErr {
+		log.Printf("devicemapper: %v; skipping LVM volume groups", err)
+		c.loggedVGErr = true
+	}
+}
@@ -3,119 +3,273 @@ package collectors
 import (
 	"bufio"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
-	"syscall"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-// DiskCollector collects disk I/O counters and root filesystem capacity.
+// DefaultDiskDeviceInclude matches physical disk device names.
+const DefaultDiskDeviceInclude = `^(sd|nvme|vd|hd|xvd|mmcblk)`
+
+// DefaultDiskDeviceExclude matches virtual, loop, and removable media devices.
+const DefaultDiskDeviceExclude = `^(loop|ram|dm-|sr|fd)`
+
+// diskSectorSize is the unit of the sector counters in /proc/diskstats,
+// which is always 512 bytes regardless of the device's logical block size.
+const diskSectorSize = 512
+
+// diskstatField maps a /proc/diskstats column to an exported metric.
+// The raw value is multiplied by scale (e.g. sectors to bytes, ms to seconds).
+type diskstatField struct {
+	index     int
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	scale     float64
+}
+
+// newDiskstatField creates a diskstatField for the given /proc/diskstats column.
+func newDiskstatField(index int, name, help string, labels []string, valueType prometheus.ValueType, scale float64) diskstatField {
+	return diskstatField{
+		index:     index,
+		desc:      prometheus.NewDesc(name, help, labels, nil),
+		valueType: valueType,
+		scale:     scale,
+	}
+}
+
+// collect sends the field's value from a /proc/diskstats line to the channel.
+// Columns missing on older kernels are skipped.
+func (f diskstatField) collect(ch chan<- prometheus.Metric, fields []string, labels ...string) {
+	if f.index >= len(fields) {
+		return
+	}
+	v, err := strconv.ParseFloat(fields[f.index], 64)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(f.desc, f.valueType, v*f.scale, labels...)
+}
+
+// DiskCollector collects disk I/O counters.
 type DiskCollector struct {
-	readsDesc  *prometheus.Desc
-	writesDesc *prometheus.Desc
-	usedDesc   *prometheus.Desc
+	fields         []diskstatField
+	infoDesc       *prometheus.Desc
+	sizeDesc       *prometheus.Desc
+	queueDepthDesc *prometheus.Desc
+
+	deviceInclude     *regexp.Regexp
+	deviceExclude     *regexp.Regexp
+	includePartitions bool
 }
 
 // NewDiskCollector creates a new DiskCollector.
-func NewDiskCollector() *DiskCollector {
+// Only devices matching deviceInclude and not matching deviceExclude are reported;
+// a nil regexp disables the respective filter.
+// If includePartitions is true, partitions are reported alongside whole devices
+// under their own device name, and all I/O metrics carry an additional "parent"
+// label naming the disk of a partition (empty for whole devices).
+func NewDiskCollector(deviceInclude, deviceExclude *regexp.Regexp, includePartitions bool) *DiskCollector {
+	labels := []string{"device"}
+	if includePartitions {
+		labels = append(labels, "parent")
+	}
+
+	// Column indices follow
+	// https://www.kernel.org/doc/Documentation/ABI/testing/procfs-diskstats
+	// (0: major, 1: minor, 2: device name, 3..: statistics).
+	field := func(index int, name, help string, valueType prometheus.ValueType, scale float64) diskstatField {
+		return newDiskstatField(index, name, help, labels, valueType, scale)
+	}
+
 	return &DiskCollector{
-		readsDesc: prometheus.NewDesc(
-			"diskio_reads_completed_total",
-			"Total number of completed disk read operations (use rate() in PromQL for IOPS)",
-			[]string{"device"}, nil,
+		fields: []diskstatField{
+			field(3, "diskio_reads_completed_total",
+				"Total number of completed disk read operations (use rate() in PromQL for IOPS)",
+				prometheus.CounterValue, 1),
+			field(4, "diskio_reads_merged_total",
+				"Total number of adjacent disk reads merged before being issued",
+				prometheus.CounterValue, 1),
+			field(5, "diskio_read_bytes_total",
+				"Total number of bytes read from disk",
+				prometheus.CounterValue, diskSectorSize),
+			field(6, "diskio_read_time_seconds_total",
+				"Total time spent on completed disk reads in seconds",
+				prometheus.CounterValue, 0.001),
+			field(7, "diskio_writes_completed_total",
+				"Total number of completed disk write operations (use rate() in PromQL for IOPS)",
+				prometheus.CounterValue, 1),
+			field(8, "diskio_writes_merged_total",
+				"Total number of adjacent disk writes merged before being issued",
+				prometheus.CounterValue, 1),
+			field(9, "diskio_written_bytes_total",
+				"Total number of bytes written to disk",
+				prometheus.CounterValue, diskSectorSize),
+			field(10, "diskio_write_time_seconds_total",
+				"Total time spent on completed disk writes in seconds",
+				prometheus.CounterValue, 0.001),
+			field(11, "diskio_io_now",
+				"Number of disk I/O requests currently in flight",
+				prometheus.GaugeValue, 1),
+			field(12, "diskio_io_time_seconds_total",
+				"Total time the device had I/O in flight in seconds (rate() gives utilization)",
+				prometheus.CounterValue, 0.001),
+			field(13, "diskio_io_time_weighted_seconds_total",
+				"Total in-flight time weighted by number of outstanding requests in seconds (rate() gives average queue size)",
+				prometheus.CounterValue, 0.001),
+			// Discard columns are only present on kernel 4.18+
+			field(14, "diskio_discards_completed_total",
+				"Total number of completed discard (TRIM) operations",
+				prometheus.CounterValue, 1),
+			field(15, "diskio_discards_merged_total",
+				"Total number of adjacent discards merged before being issued",
+				prometheus.CounterValue, 1),
+			field(16, "diskio_discarded_bytes_total",
+				"Total number of bytes discarded",
+				prometheus.CounterValue, diskSectorSize),
+			field(17, "diskio_discard_time_seconds_total",
+				"Total time spent on completed discards in seconds",
+				prometheus.CounterValue, 0.001),
+		},
+		infoDesc: prometheus.NewDesc(
+			"diskio_device_info",
+			"Block device queue configuration (always 1)",
+			[]string{"device", "scheduler", "rotational"}, nil,
 		),
-		writesDesc: prometheus.NewDesc(
-			"diskio_writes_completed_total",
-			"Total number of completed disk write operations (use rate() in PromQL for IOPS)",
+		sizeDesc: prometheus.NewDesc(
+			"diskio_device_size_bytes",
+			"Size of the block device in bytes",
 			[]string{"device"}, nil,
 		),
-		usedDesc: prometheus.NewDesc(
-			"storage_used_percent",
-			"Used storage capacity of / filesystem in percent",
-			nil, nil,
+		queueDepthDesc: prometheus.NewDesc(
+			"diskio_device_queue_depth",
+			"Maximum number of requests the block layer queues for the device (nr_requests)",
+			[]string{"device"}, nil,
 		),
+		deviceInclude:     deviceInclude,
+		deviceExclude:     deviceExclude,
+		includePartitions: includePartitions,
 	}
 }
 
 // Describe sends metric descriptors to the channel.
 func (c *DiskCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.readsDesc
-	ch <- c.writesDesc
-	ch <- c.usedDesc
+	for _, f := range c.fields {
+		ch <- f.desc
+	}
+	ch <- c.infoDesc
+	ch <- c.sizeDesc
+	ch <- c.queueDepthDesc
 }
 
-// Collect reads disk I/O stats and root capacity, sending them to the channel.
+// Collect reads disk I/O stats, sending them to the channel.
 func (c *DiskCollector) Collect(ch chan<- prometheus.Metric) {
 	c.collectDiskIO(ch)
-	c.collectRootCapacity(ch)
 }
 
-// collectDiskIO reads /proc/diskstats for physical disk devices.
+// collectDiskIO reads /proc/diskstats for the selected disk devices and, if enabled, their partitions.
 func (c *DiskCollector) collectDiskIO(ch chan<- prometheus.Metric) {
-	f, err := os.Open("/proc/diskstats")
+	stats, err := readDiskstats()
 	if err != nil {
 		return
 	}
-	defer f.Close()
-
-	physicalPrefixes := []string{"sd", "nvme", "vd", "hd", "xvd", "mmcblk"}
-	excludePrefixes := []string{"loop", "ram", "dm-", "sr", "fd"}
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		fields := strings.Fields(scanner.Text())
-		if len(fields) < 14 {
-			continue
-		}
 
+	for _, fields := range stats {
 		device := fields[2]
 
-		// Skip excluded devices
-		if hasAnyPrefix(device, excludePrefixes) {
+		if c.deviceInclude != nil && !c.deviceInclude.MatchString(device) {
 			continue
 		}
-
-		// Only include physical devices
-		if !hasAnyPrefix(device, physicalPrefixes) {
+		if c.deviceExclude != nil && c.deviceExclude.MatchString(device) {
 			continue
 		}
 
-		// Field 3: reads completed, Field 7: writes completed
-		// See https://www.kernel.org/doc/Documentation/ABI/testing/procfs-diskstats
-		reads, _ := strconv.ParseFloat(fields[3], 64)
-		writes, _ := strconv.ParseFloat(fields[7], 64)
+		labels := []string{device}
+		if parent, isPartition := partitionParent(device); isPartition {
+			if !c.includePartitions {
+				continue
+			}
+			labels = append(labels, parent)
+		} else {
+			c.collectDeviceInfo(ch, device)
+			if c.includePartitions {
+				labels = append(labels, "")
+			}
+		}
 
-		ch <- prometheus.MustNewConstMetric(c.readsDesc, prometheus.CounterValue, reads, device)
-		ch <- prometheus.MustNewConstMetric(c.writesDesc, prometheus.CounterValue, writes, device)
+		for _, f := range c.fields {
+			f.collect(ch, fields, labels...)
+		}
 	}
 }
 
-// collectRootCapacity reports the used capacity percentage of the / filesystem.
-func (c *DiskCollector) collectRootCapacity(ch chan<- prometheus.Metric) {
-	var stat syscall.Statfs_t
-	if err := syscall.Statfs("/", &stat); err != nil {
+// collectDeviceInfo reports the queue configuration and size of a whole block device from /sys/block.
+func (c *DiskCollector) collectDeviceInfo(ch chan<- prometheus.Metric, device string) {
+	sysPath := filepath.Join("/sys/block", device)
+	queuePath := filepath.Join(sysPath, "queue")
+	if _, err := os.Stat(queuePath); err != nil {
 		return
 	}
 
-	total := stat.Blocks * uint64(stat.Bsize)
-	available := stat.Bavail * uint64(stat.Bsize)
+	ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1,
+		device,
+		activeScheduler(readSysString(filepath.Join(queuePath, "scheduler"))),
+		readSysString(filepath.Join(queuePath, "rotational")),
+	)
 
-	if total == 0 {
-		return
-	}
+	// The size attribute is always in 512-byte sectors
+	sectors := readSysUint64(filepath.Join(sysPath, "size"))
+	ch <- prometheus.MustNewConstMetric(c.sizeDesc, prometheus.GaugeValue, float64(sectors)*diskSectorSize, device)
 
-	// Used percentage from the user's perspective (total - available) / total
-	usedPercent := float64(total-available) / float64(total) * 100.0
-	ch <- prometheus.MustNewConstMetric(c.usedDesc, prometheus.GaugeValue, usedPercent)
+	queueDepth := readSysUint64(filepath.Join(queuePath, "nr_requests"))
+	ch <- prometheus.MustNewConstMetric(c.queueDepthDesc, prometheus.GaugeValue, float64(queueDepth), device)
 }
 
-// hasAnyPrefix checks if s starts with any of the given prefixes.
-func hasAnyPrefix(s string, prefixes []string) bool {
-	for _, p := range prefixes {
-		if strings.HasPrefix(s, p) {
-			return true
+// activeScheduler extracts the selected I/O scheduler from a queue/scheduler
+// attribute such as "mq-deadline kyber [bfq] none".
+func activeScheduler(s string) string {
+	start := strings.Index(s, "[")
+	end := strings.Index(s, "]")
+	if start < 0 || end < start {
+		return s
+	}
+	return s[start+1 : end]
+}
+
+// readDiskstats returns the whitespace-separated columns of every /proc/diskstats line.
+func readDiskstats() ([][]string, error) {
+	f, err := os.Open("/proc/diskstats")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var stats [][]string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 14 {
+			continue
 		}
+		stats = append(stats, fields)
+	}
+	return stats, scanner.Err()
+}
+
+// partitionParent reports whether the block device is a partition and, if so,
+// returns the name of the whole device it belongs to (e.g. nvme0n1p1 -> nvme0n1).
+func partitionParent(device string) (string, bool) {
+	sysPath := filepath.Join("/sys/class/block", device)
+	if _, err := os.Stat(filepath.Join(sysPath, "partition")); err != nil {
+		return "", false
+	}
+
+	// /sys/class/block/<partition> links into the parent device's sysfs directory
+	resolved, err := filepath.EvalSymlinks(sysPath)
+	if err != nil {
+		return "", true
 	}
-	return false
+	return filepath.Base(filepath.Dir(resolved)), true
 }
@@ -0,0 +1,157 @@
+package collectors
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// drmClassDir lists DRM devices and their display connectors (card0-HDMI-A-1, ...).
+const drmClassDir = "/sys/class/drm"
+
+// displayTimeout bounds the logind D-Bus exchange of a single scrape.
+const displayTimeout = 2 * time.Second
+
+// DisplayCollector reports whether a display is attached and a graphical session
+// is running. A desktop compositor reserves GPU memory, which explains capacity
+// differences between otherwise identical headless and desktop machines.
+type DisplayCollector struct {
+	connectedDesc *prometheus.Desc
+	modeDesc      *prometheus.Desc
+
+	mu        sync.Mutex
+	loggedErr bool
+}
+
+// NewDisplayCollector creates a new DisplayCollector.
+func NewDisplayCollector() *DisplayCollector {
+	return &DisplayCollector{
+		connectedDesc: prometheus.NewDesc(
+			"display_connected",
+			"Whether a display is connected to the DRM connector (1 = connected)",
+			[]string{"connector"}, nil,
+		),
+		modeDesc: prometheus.NewDesc(
+			"display_mode_info",
+			"Display mode of the machine, always 1 (mode = headless, console, or desktop; session_type = x11, wayland, or empty)",
+			[]string{"mode", "session_type"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *DisplayCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connectedDesc
+	ch <- c.modeDesc
+}
+
+// Collect reads the connector status of all DRM devices and the graphical
+// sessions known to logind, and sends the display state to the channel. The mode
+// is desktop while a graphical session (including a display manager's login
+// screen) runs, console if a display is connected without one, and headless
+// otherwise.
+func (c *DisplayCollector) Collect(ch chan<- prometheus.Metric) {
+	connected := false
+	connectors, _ := filepath.Glob(filepath.Join(drmClassDir, "card*-*"))
+	for _, dir := range connectors {
+		status := readSysString(filepath.Join(dir, "status"))
+		if status == "" {
+			continue
+		}
+		// Strip the card prefix: card0-HDMI-A-1 -> HDMI-A-1
+		_, name, _ := strings.Cut(filepath.Base(dir), "-")
+		v := 0.0
+		if status == "connected" {
+			v = 1
+			connected = true
+		}
+		ch <- prometheus.MustNewConstMetric(c.connectedDesc, prometheus.GaugeValue, v, name)
+	}
+
+	sessionType, err := graphicalSessionType()
+	if err != nil {
+		c.mu.Lock()
+		if !c.loggedErr {
+			log.Printf("display: %v; detecting graphical sessions from running display servers", err)
+			c.loggedErr = true
+		}
+		c.mu.Unlock()
+		sessionType = fallbackGraphicalSessionType()
+	}
+
+	mode := "headless"
+	switch {
+	case sessionType != "":
+		mode = "desktop"
+	case connected:
+		mode = "console"
+	}
+	ch <- prometheus.MustNewConstMetric(c.modeDesc, prometheus.GaugeValue, 1, mode, sessionType)
+}
+
+// graphicalSessionType returns the type (x11 or wayland) of a graphical session
+// known to logind, or an empty string if there is none.
+func graphicalSessionType() (string, error) {
+	const dest = "org.freedesktop.login1"
+
+	conn, err := dialSystemBus(time.Now().Add(displayTimeout))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	reply, err := conn.call(dest, "/org/freedesktop/login1", "org.freedesktop.login1.Manager", "ListSessions")
+	if err != nil {
+		return "", err
+	}
+	// (session ID, user ID, user name, seat, session path)
+	if reply.signature != "a(susso)" {
+		return "", fmt.Errorf("unexpected ListSessions reply signature %q", reply.signature)
+	}
+
+	d := reply.decoder()
+	size := int(d.uint32())
+	d.align(8)
+	end := d.pos + size
+
+	var paths []string
+	for d.err == nil && d.pos < end {
+		d.align(8)
+		d.string() // session ID
+		d.uint32() // user ID
+		d.string() // user name
+		d.string() // seat
+		paths = append(paths, d.string())
+	}
+	if d.err != nil {
+		return "", d.err
+	}
+
+	for _, path := range paths {
+		typ, err := conn.getStringProperty(dest, path, "org.freedesktop.login1.Session", "Type")
+		if err != nil {
+			return "", err
+		}
+		if typ == "x11" || typ == "wayland" {
+			return typ, nil
+		}
+	}
+	return "", nil
+}
+
+// fallbackGraphicalSessionType detects a graphical session from the running
+// display servers when logind cannot be queried.
+func fallbackGraphicalSessionType() string {
+	switch {
+	case processRunning("Xorg"):
+		return "x11"
+	case processRunning("Xwayland") || processRunning("gnome-shell"):
+		return "wayland"
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package collectors
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dmiIDDir exposes the SMBIOS system and board identifiers.
+const dmiIDDir = "/sys/class/dmi/id"
+
+// deviceTreeDir is used for the model and serial number on systems without SMBIOS.
+const deviceTreeDir = "/proc/device-tree"
+
+// dmiInfoAttributes are the /sys/class/dmi/id attributes exported as labels of dmi_info.
+var dmiInfoAttributes = []string{
+	"sys_vendor",
+	"product_name",
+	"product_version",
+	"product_serial",
+	"product_sku",
+	"board_vendor",
+	"board_name",
+	"board_version",
+	"board_serial",
+	"chassis_serial",
+}
+
+// DMICollector exports the hardware identity of the machine from SMBIOS (DMI).
+type DMICollector struct {
+	infoDesc *prometheus.Desc
+}
+
+// NewDMICollector creates a new DMICollector.
+func NewDMICollector() *DMICollector {
+	return &DMICollector{
+		infoDesc: prometheus.NewDesc(
+			"dmi_info",
+			"Hardware identity of the machine from SMBIOS, always 1",
+			dmiInfoAttributes, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *DMICollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+}
+
+// Collect reads the DMI attributes and sends the info metric to the channel. Serial
+// numbers are only readable by root and are empty otherwise. Without SMBIOS, the
+// product name and serial number are taken from the device tree.
+func (c *DMICollector) Collect(ch chan<- prometheus.Metric) {
+	values := make([]string, len(dmiInfoAttributes))
+	found := false
+	for i, attr := range dmiInfoAttributes {
+		values[i] = readSysString(filepath.Join(dmiIDDir, attr))
+		if values[i] != "" {
+			found = true
+		}
+	}
+
+	if !found {
+		// Device tree strings are NUL-terminated
+		values[1] = strings.TrimRight(readSysString(filepath.Join(deviceTreeDir, "model")), "\x00")
+		values[3] = strings.TrimRight(readSysString(filepath.Join(deviceTreeDir, "serial-number")), "\x00")
+		if values[1] == "" && values[3] == "" {
+			return
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, values...)
+}
@@ -0,0 +1,101 @@
+package collectors
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DNSProbeSystemResolver is the server label of lookups through the system resolver
+// configuration (/etc/resolv.conf).
+const DNSProbeSystemResolver = "system"
+
+// DNSProbeCollector resolves a list of host names on every scrape and reports
+// resolution latency and success.
+type DNSProbeCollector struct {
+	successDesc   *prometheus.Desc
+	durationDesc  *prometheus.Desc
+	addressesDesc *prometheus.Desc
+
+	names    []string
+	server   string
+	resolver *net.Resolver
+	timeout  time.Duration
+}
+
+// NewDNSProbeCollector creates a new DNSProbeCollector. Names are resolved through
+// server (host:port) if set, otherwise through the system resolver; each lookup is
+// abandoned after timeout.
+func NewDNSProbeCollector(names []string, server string, timeout time.Duration) *DNSProbeCollector {
+	labels := []string{"name", "server"}
+	c := &DNSProbeCollector{
+		successDesc: prometheus.NewDesc(
+			"dns_probe_success",
+			"Whether the name resolved to at least one address (1 = success, 0 = failure)",
+			labels, nil,
+		),
+		durationDesc: prometheus.NewDesc(
+			"dns_probe_duration_seconds",
+			"Time taken to resolve the name in seconds, including failed lookups",
+			labels, nil,
+		),
+		addressesDesc: prometheus.NewDesc(
+			"dns_probe_addresses",
+			"Number of addresses the name resolved to",
+			labels, nil,
+		),
+		names:    names,
+		server:   DNSProbeSystemResolver,
+		resolver: net.DefaultResolver,
+		timeout:  timeout,
+	}
+
+	if server != "" {
+		c.server = server
+		c.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	return c
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *DNSProbeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.successDesc
+	ch <- c.durationDesc
+	ch <- c.addressesDesc
+}
+
+// Collect resolves all names concurrently and sends the results to the channel.
+func (c *DNSProbeCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			addrs, err := c.resolver.LookupHost(ctx, name)
+			duration := time.Since(start).Seconds()
+
+			success := 0.0
+			if err == nil && len(addrs) > 0 {
+				success = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.successDesc, prometheus.GaugeValue, success, name, c.server)
+			ch <- prometheus.MustNewConstMetric(c.durationDesc, prometheus.GaugeValue, duration, name, c.server)
+			ch <- prometheus.MustNewConstMetric(c.addressesDesc, prometheus.GaugeValue, float64(len(addrs)), name, c.server)
+		}(name)
+	}
+	wg.Wait()
+}
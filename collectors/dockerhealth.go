@@ -0,0 +1,197 @@
+package collectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultDockerSocket is the API socket of the Docker daemon.
+const DefaultDockerSocket = "/var/run/docker.sock"
+
+// Docker health check timing. The check runs nvidia-container-cli, which
+// initializes the driver, so its result is cached between scrapes.
+const (
+	dockerHealthCacheTTL  = time.Minute
+	dockerAPITimeout      = 5 * time.Second
+	nvidiaContainerCLIMax = 30 * time.Second
+)
+
+// dockerHealth is the result of one Docker health check.
+type dockerHealth struct {
+	up             bool
+	version        string
+	defaultRuntime string
+	runtimes       []string
+	nvidiaRuntime  bool
+	cliSuccess     bool
+	errs           []error // failures of the check, reported until the next one
+}
+
+// DockerHealthCollector checks that the Docker daemon is running and can start GPU
+// containers: the nvidia runtime is registered with the daemon and the NVIDIA
+// container library can access the driver.
+type DockerHealthCollector struct {
+	upDesc            *prometheus.Desc
+	infoDesc          *prometheus.Desc
+	runtimeDesc       *prometheus.Desc
+	nvidiaRuntimeDesc *prometheus.Desc
+	cliSuccessDesc    *prometheus.Desc
+	nvidiaHealthyDesc *prometheus.Desc
+
+	cliPath string
+	client  *http.Client
+
+	mu      sync.Mutex
+	checked time.Time
+	health  dockerHealth
+}
+
+// NewDockerHealthCollector creates a new DockerHealthCollector talking to the
+// Docker daemon on socket and checking driver access with the nvidia-container-cli
+// binary at cliPath.
+func NewDockerHealthCollector(socket, cliPath string) *DockerHealthCollector {
+	return &DockerHealthCollector{
+		upDesc: prometheus.NewDesc(
+			"docker_up",
+			"Whether the Docker daemon answered on its API socket (1 = up)",
+			nil, nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			"docker_info",
+			"Docker daemon version and default runtime, always 1",
+			[]string{"version", "default_runtime"}, nil,
+		),
+		runtimeDesc: prometheus.NewDesc(
+			"docker_runtime_info",
+			"Container runtime registered with the Docker daemon, always 1",
+			[]string{"runtime"}, nil,
+		),
+		nvidiaRuntimeDesc: prometheus.NewDesc(
+			"nvidia_container_runtime_registered",
+			"Whether the nvidia runtime is registered with the Docker daemon (1 = registered)",
+			nil, nil,
+		),
+		cliSuccessDesc: prometheus.NewDesc(
+			"nvidia_container_cli_success",
+			"Whether nvidia-container-cli info could access the driver and GPUs (1 = success)",
+			nil, nil,
+		),
+		nvidiaHealthyDesc: prometheus.NewDesc(
+			"nvidia_container_runtime_healthy",
+			"Whether Docker can run GPU containers: daemon up, nvidia runtime registered, and driver accessible (1 = healthy)",
+			nil, nil,
+		),
+		cliPath: cliPath,
+		client:  newDockerClient(socket),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *DockerHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upDesc
+	ch <- c.infoDesc
+	ch <- c.runtimeDesc
+	ch <- c.nvidiaRuntimeDesc
+	ch <- c.cliSuccessDesc
+	ch <- c.nvidiaHealthyDesc
+}
+
+// Collect sends the result of the latest health check to the channel, running a
+// new check if the cached one is older than a minute.
+func (c *DockerHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing nvidia-container-cli when ctx is done.
+func (c *DockerHealthCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	if time.Since(c.checked) >= dockerHealthCacheTTL {
+		// A check cut short by ctx says nothing about the health; do not cache it
+		if h := c.check(ctx); ctx.Err() == nil {
+			c.health = h
+			c.checked = time.Now()
+		}
+	}
+	h := c.health
+	c.mu.Unlock()
+
+	for _, err := range h.errs {
+		ch <- prometheus.NewInvalidMetric(c.upDesc, err)
+	}
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, boolToFloat(h.up))
+	if h.up {
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, h.version, h.defaultRuntime)
+		for _, runtime := range h.runtimes {
+			ch <- prometheus.MustNewConstMetric(c.runtimeDesc, prometheus.GaugeValue, 1, runtime)
+		}
+		ch <- prometheus.MustNewConstMetric(c.nvidiaRuntimeDesc, prometheus.GaugeValue, boolToFloat(h.nvidiaRuntime))
+	}
+	ch <- prometheus.MustNewConstMetric(c.cliSuccessDesc, prometheus.GaugeValue, boolToFloat(h.cliSuccess))
+	ch <- prometheus.MustNewConstMetric(c.nvidiaHealthyDesc, prometheus.GaugeValue, boolToFloat(h.up && h.nvidiaRuntime && h.cliSuccess))
+}
+
+// check queries the daemon and runs nvidia-container-cli.
+func (c *DockerHealthCollector) check(ctx context.Context) dockerHealth {
+	var h dockerHealth
+
+	var info struct {
+		ServerVersion  string              `json:"ServerVersion"`
+		DefaultRuntime string              `json:"DefaultRuntime"`
+		Runtimes       map[string]struct{} `json:"Runtimes"`
+	}
+	if err := getDocker(c.client, "/info", &info); err != nil {
+		h.errs = append(h.errs, err)
+	} else {
+		h.up = true
+		h.version = info.ServerVersion
+		h.defaultRuntime = info.DefaultRuntime
+		for runtime := range info.Runtimes {
+			h.runtimes = append(h.runtimes, runtime)
+		}
+		_, h.nvidiaRuntime = info.Runtimes["nvidia"]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, nvidiaContainerCLIMax)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, c.cliPath, "info").CombinedOutput(); err != nil {
+		h.errs = append(h.errs, fmt.Errorf("%s info: %w: %s", c.cliPath, err, bytes.TrimSpace(out)))
+	} else {
+		h.cliSuccess = true
+	}
+	return h
+}
+
+// newDockerClient returns an HTTP client for the Docker Engine API on socket.
+func newDockerClient(socket string) *http.Client {
+	return &http.Client{
+		Timeout: dockerAPITimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+// getDocker fetches a Docker Engine API resource and decodes it into v.
+func getDocker(client *http.Client, path string, v any) error {
+	resp, err := client.Get("http://docker" + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
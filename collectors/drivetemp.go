@@ -0,0 +1,134 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// hwmonSensor is a single <type><n>_input (or other per-sensor) attribute of a hwmon chip.
+type hwmonSensor struct {
+	// name is the sensor attribute prefix, e.g. "temp1"
+	name string
+	// label is the content of <name>_label, or name if the chip provides no label
+	label string
+	// value is the raw content of the attribute
+	value float64
+}
+
+// DriveTempCollector collects drive temperatures from the nvme and drivetemp hwmon drivers.
+type DriveTempCollector struct {
+	tempDesc *prometheus.Desc
+}
+
+// NewDriveTempCollector creates a new DriveTempCollector.
+func NewDriveTempCollector() *DriveTempCollector {
+	return &DriveTempCollector{
+		tempDesc: prometheus.NewDesc(
+			"disk_temperature_celsius",
+			"Drive temperature sensor reading in degrees Celsius",
+			[]string{"device", "sensor"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *DriveTempCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tempDesc
+}
+
+// Collect reads drive temperature sensors and sends them to the channel.
+func (c *DriveTempCollector) Collect(ch chan<- prometheus.Metric) {
+	chips, err := filepath.Glob("/sys/class/hwmon/hwmon*")
+	if err != nil {
+		return
+	}
+
+	for _, chip := range chips {
+		var device string
+		switch readSysString(filepath.Join(chip, "name")) {
+		case "nvme":
+			// device links to the NVMe controller, e.g. .../nvme/nvme0
+			device = hwmonDeviceName(chip)
+		case "drivetemp":
+			// device links to the SCSI device; its block device is listed under block/
+			device = scsiBlockDevice(filepath.Join(chip, "device"))
+		default:
+			continue
+		}
+		if device == "" {
+			continue
+		}
+
+		// Labels are not guaranteed to be unique within a chip
+		seenLabels := make(map[string]bool)
+		for _, s := range readHwmonSensors(chip, "temp") {
+			if seenLabels[s.label] {
+				s.label = s.name
+			}
+			seenLabels[s.label] = true
+			ch <- prometheus.MustNewConstMetric(c.tempDesc, prometheus.GaugeValue, s.value/1000.0, device, s.label)
+		}
+	}
+}
+
+// readHwmonSensors returns all sensors of the given type (temp, fan, in, curr, power, ...)
+// of a hwmon chip directory, sorted by attribute name.
+func readHwmonSensors(chip, sensorType string) []hwmonSensor {
+	return readHwmonAttributes(chip, sensorType, "_input")
+}
+
+// readHwmonAttributes returns the <sensorType><n><suffix> attributes of a hwmon chip
+// directory, e.g. fan1_target or (with an empty suffix) pwm1, sorted by attribute name.
+func readHwmonAttributes(chip, sensorType, suffix string) []hwmonSensor {
+	inputs, err := filepath.Glob(filepath.Join(chip, sensorType+"*"+suffix))
+	if err != nil {
+		return nil
+	}
+	sort.Strings(inputs)
+
+	var sensors []hwmonSensor
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), suffix)
+
+		// Guard against prefixes of other types, e.g. "in" vs "intrusion", and
+		// against other attributes of the sensor, e.g. "pwm1_enable"
+		if _, err := strconv.Atoi(strings.TrimPrefix(name, sensorType)); err != nil {
+			continue
+		}
+
+		value, ok := readHwmonValue(input)
+		if !ok {
+			continue
+		}
+
+		label := readSysString(filepath.Join(chip, name+"_label"))
+		if label == "" {
+			label = name
+		}
+		sensors = append(sensors, hwmonSensor{name: name, label: label, value: value})
+	}
+	return sensors
+}
+
+// hwmonDeviceName returns the base name of the device a hwmon chip belongs to.
+func hwmonDeviceName(chip string) string {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(chip, "device"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(resolved)
+}
+
+// scsiBlockDevice returns the block device name (e.g. sda) of a SCSI device directory.
+func scsiBlockDevice(scsiDir string) string {
+	entries, err := os.ReadDir(filepath.Join(scsiDir, "block"))
+	if err != nil || len(entries) == 0 {
+		return ""
+	}
+	return entries[0].Name()
+}
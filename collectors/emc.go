@@ -0,0 +1,103 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Tegra memory controller (EMC) clock and activity monitor nodes. The clock is read
+// from the BPMP clock tree (Orin and later), the legacy clock tree, or the EMC
+// devfreq device, whichever exists.
+const (
+	emcBPMPClockDir   = "/sys/kernel/debug/bpmp/debug/clk/emc"
+	emcLegacyClockDir = "/sys/kernel/debug/clk/emc"
+	emcActmonFile     = "/sys/kernel/actmon_avg_activity/mc_all"
+)
+
+// EMCCollector collects the external memory controller frequency and utilization
+// of Tegra-style SoCs.
+type EMCCollector struct {
+	freqDesc        *prometheus.Desc
+	maxFreqDesc     *prometheus.Desc
+	utilizationDesc *prometheus.Desc
+}
+
+// NewEMCCollector creates a new EMCCollector.
+func NewEMCCollector() *EMCCollector {
+	return &EMCCollector{
+		freqDesc: prometheus.NewDesc(
+			"emc_frequency_hz",
+			"Current memory controller (EMC) clock frequency in Hz",
+			nil, nil,
+		),
+		maxFreqDesc: prometheus.NewDesc(
+			"emc_max_frequency_hz",
+			"Maximum memory controller (EMC) clock frequency in Hz",
+			nil, nil,
+		),
+		utilizationDesc: prometheus.NewDesc(
+			"emc_utilization_percent",
+			"Memory controller activity relative to the current EMC frequency (0-100)",
+			nil, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *EMCCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.freqDesc
+	ch <- c.maxFreqDesc
+	ch <- c.utilizationDesc
+}
+
+// Collect reads the EMC clock and activity monitor and sends them to the channel.
+// The clock trees live in debugfs, which is only readable by root. If no EMC
+// clock is exposed, no metrics are emitted.
+func (c *EMCCollector) Collect(ch chan<- prometheus.Metric) {
+	rate, maxRate, ok := readEMCClock()
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.freqDesc, prometheus.GaugeValue, rate)
+	if maxRate > 0 {
+		ch <- prometheus.MustNewConstMetric(c.maxFreqDesc, prometheus.GaugeValue, maxRate)
+	}
+
+	// The activity monitor reports the average busy EMC clock cycles in kHz
+	if activity, ok := readProcSysFloat(emcActmonFile); ok && rate > 0 {
+		util := activity * 1000 / rate * 100
+		if util > 100 {
+			util = 100
+		}
+		ch <- prometheus.MustNewConstMetric(c.utilizationDesc, prometheus.GaugeValue, util)
+	}
+}
+
+// readEMCClock returns the current and maximum EMC clock rate in Hz. The maximum is
+// 0 if unknown.
+func readEMCClock() (rate, maxRate float64, ok bool) {
+	if rate, ok = readProcSysFloat(filepath.Join(emcBPMPClockDir, "rate")); ok {
+		maxRate, _ = readProcSysFloat(filepath.Join(emcBPMPClockDir, "max_rate"))
+		return rate, maxRate, true
+	}
+	if rate, ok = readProcSysFloat(filepath.Join(emcLegacyClockDir, "clk_rate")); ok {
+		maxRate, _ = readProcSysFloat(filepath.Join(emcLegacyClockDir, "clk_max_rate"))
+		return rate, maxRate, true
+	}
+
+	devices, _ := os.ReadDir(devfreqClassDir)
+	for _, e := range devices {
+		if !strings.Contains(e.Name(), "emc") {
+			continue
+		}
+		dir := filepath.Join(devfreqClassDir, e.Name())
+		if rate, ok = readProcSysFloat(filepath.Join(dir, "cur_freq")); ok {
+			maxRate, _ = maxAvailableFrequency(filepath.Join(dir, "available_frequencies"))
+			return rate, maxRate, true
+		}
+	}
+	return 0, 0, false
+}
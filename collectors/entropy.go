@@ -0,0 +1,43 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// EntropyCollector collects the available entropy of the kernel random number generator.
+type EntropyCollector struct {
+	availableDesc *prometheus.Desc
+	poolSizeDesc  *prometheus.Desc
+}
+
+// NewEntropyCollector creates a new EntropyCollector.
+func NewEntropyCollector() *EntropyCollector {
+	return &EntropyCollector{
+		availableDesc: prometheus.NewDesc(
+			"node_entropy_available_bits",
+			"Entropy available in the kernel random pool in bits",
+			nil, nil,
+		),
+		poolSizeDesc: prometheus.NewDesc(
+			"node_entropy_pool_size_bits",
+			"Size of the kernel random pool in bits",
+			nil, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *EntropyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.availableDesc
+	ch <- c.poolSizeDesc
+}
+
+// Collect reads /proc/sys/kernel/random and sends the entropy metrics to the channel.
+func (c *EntropyCollector) Collect(ch chan<- prometheus.Metric) {
+	if v, ok := readProcSysFloat("/proc/sys/kernel/random/entropy_avail"); ok {
+		ch <- prometheus.MustNewConstMetric(c.availableDesc, prometheus.GaugeValue, v)
+	}
+	if v, ok := readProcSysFloat("/proc/sys/kernel/random/poolsize"); ok {
+		ch <- prometheus.MustNewConstMetric(c.poolSizeDesc, prometheus.GaugeValue, v)
+	}
+}
@@ -0,0 +1,286 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// siocEthtool is SIOCETHTOOL from <linux/sockios.h>.
+	siocEthtool = 0x8946
+
+	ethtoolGDrvInfo  = 0x03
+	ethtoolGStrings  = 0x1b
+	ethtoolGStats    = 0x1d
+	ethtoolGSsetInfo = 0x37
+
+	ethSSStats    = 1
+	ethGStringLen = 32
+	ifNameSize    = 16
+
+	// ethtoolDrvInfoSize is sizeof(struct ethtool_drvinfo).
+	ethtoolDrvInfoSize = 196
+)
+
+// ethtoolQueueStat matches per-queue driver statistics such as rx0_packets (mlx5)
+// or tx_queue_3_bytes (virtio, ixgbe).
+var ethtoolQueueStat = regexp.MustCompile(`^(rx|tx)(?:_queue_)?(\d+)_(.+)$`)
+
+// ethtoolPriorityStat matches per-priority (PFC) statistics such as rx_prio3_pause (mlx5).
+var ethtoolPriorityStat = regexp.MustCompile(`^(rx|tx)_prio(\d+)_(.+)$`)
+
+// ethtoolIfreq mirrors struct ifreq with ifr_data set, as used by SIOCETHTOOL.
+type ethtoolIfreq struct {
+	name [ifNameSize]byte
+	data uintptr
+	_    [16]byte
+}
+
+// ethtoolSocket issues SIOCETHTOOL ioctls on a datagram socket.
+type ethtoolSocket struct {
+	fd int
+}
+
+// openEthtoolSocket opens a socket suitable for SIOCETHTOOL requests.
+func openEthtoolSocket() (*ethtoolSocket, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &ethtoolSocket{fd: fd}, nil
+}
+
+// Close closes the underlying socket.
+func (s *ethtoolSocket) Close() error {
+	return syscall.Close(s.fd)
+}
+
+// ioctl runs an ethtool command on iface. buf holds the command structure (starting
+// with the u32 command number) and receives the kernel's reply.
+func (s *ethtoolSocket) ioctl(iface string, buf []byte) error {
+	// The kernel reads and writes buf through the address stored in the ifreq
+	var pinner runtime.Pinner
+	pinner.Pin(&buf[0])
+	defer pinner.Unpin()
+
+	var ifr ethtoolIfreq
+	copy(ifr.name[:ifNameSize-1], iface)
+	ifr.data = uintptr(unsafe.Pointer(&buf[0]))
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(s.fd), siocEthtool, uintptr(unsafe.Pointer(&ifr)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// driverName returns the name of the kernel driver behind iface (e.g. mlx5_core, team).
+func (s *ethtoolSocket) driverName(iface string) (string, error) {
+	// struct ethtool_drvinfo: cmd, driver[32], version[32], ...
+	info := make([]byte, ethtoolDrvInfoSize)
+	binary.NativeEndian.PutUint32(info[0:], ethtoolGDrvInfo)
+	if err := s.ioctl(iface, info); err != nil {
+		return "", err
+	}
+	return nullTerminated(info[4 : 4+32]), nil
+}
+
+// ethtoolStatsHeadroom is the number of extra entries allocated for the string
+// and value tables, so a few statistics added between the ioctls (drivers such as
+// mlx5 add them when channels or features change) do not need another attempt.
+const ethtoolStatsHeadroom = 64
+
+// ethtoolStatsRetries bounds how often stats re-reads the tables after the
+// statistics count changed between the ioctls.
+const ethtoolStatsRetries = 3
+
+// stats returns the driver statistics of iface as parallel name and value slices.
+// Names are unique: some drivers (e.g. ixgbe, i40e) report a name more than once,
+// which would give duplicate series, so only its first value is kept.
+func (s *ethtoolSocket) stats(iface string) ([]string, []uint64, error) {
+	for attempt := 0; attempt < ethtoolStatsRetries; attempt++ {
+		n, err := s.statsCount(iface)
+		if err != nil || n == 0 {
+			return nil, nil, err
+		}
+		names, values, err := s.readStats(iface, n, int(n)+ethtoolStatsHeadroom)
+		if errors.Is(err, syscall.EFAULT) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		// Names and values only line up if the count did not change in between
+		if names == nil {
+			continue
+		}
+		return names, values, nil
+	}
+	return nil, nil, fmt.Errorf("%s: statistics count keeps changing", iface)
+}
+
+// readStats reads n statistics names and values of iface into tables with room
+// for size entries. The kernel copies out the driver's current count rather than
+// the one passed in, so the tables are mapped in front of a guard page: if the
+// count grew beyond size, the copy fails with EFAULT instead of overwriting
+// memory. nil slices are returned if the count differs from n.
+func (s *ethtoolSocket) readStats(iface string, n uint32, size int) ([]string, []uint64, error) {
+	// struct ethtool_gstrings: cmd, string_set, len, data[len * ETH_GSTRING_LEN]
+	strs, unmapStrs, err := mapGuardedBuffer(12 + size*ethGStringLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unmapStrs()
+	binary.NativeEndian.PutUint32(strs[0:], ethtoolGStrings)
+	binary.NativeEndian.PutUint32(strs[4:], ethSSStats)
+	binary.NativeEndian.PutUint32(strs[8:], n)
+	if err := s.ioctl(iface, strs); err != nil {
+		return nil, nil, err
+	}
+
+	// struct ethtool_stats: cmd, n_stats, data[n_stats]
+	vals, unmapVals, err := mapGuardedBuffer(8 + size*8)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unmapVals()
+	binary.NativeEndian.PutUint32(vals[0:], ethtoolGStats)
+	binary.NativeEndian.PutUint32(vals[4:], n)
+	if err := s.ioctl(iface, vals); err != nil {
+		return nil, nil, err
+	}
+
+	if binary.NativeEndian.Uint32(strs[8:]) != n || binary.NativeEndian.Uint32(vals[4:]) != n {
+		return nil, nil, nil
+	}
+	names := make([]string, 0, n)
+	values := make([]uint64, 0, n)
+	seen := make(map[string]bool, n)
+	for i := 0; i < int(n); i++ {
+		name := nullTerminated(strs[12+i*ethGStringLen : 12+(i+1)*ethGStringLen])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		values = append(values, binary.NativeEndian.Uint64(vals[8+i*8:]))
+	}
+	return names, values, nil
+}
+
+// mapGuardedBuffer maps an anonymous buffer of size bytes outside the Go heap that
+// ends right before an inaccessible guard page. The returned function unmaps it.
+func mapGuardedBuffer(size int) ([]byte, func(), error) {
+	page := os.Getpagesize()
+	mapped := (size + page - 1) / page * page
+	mem, err := unix.Mmap(-1, 0, mapped+page, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := unix.Mprotect(mem[mapped:], unix.PROT_NONE); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, err
+	}
+	return mem[mapped-size : mapped], func() { unix.Munmap(mem) }, nil
+}
+
+// statsCount returns the number of driver statistics of iface, or 0 if the driver
+// has none.
+func (s *ethtoolSocket) statsCount(iface string) (uint32, error) {
+	// struct ethtool_sset_info: cmd, reserved, sset_mask, data[]
+	info := make([]byte, 24)
+	binary.NativeEndian.PutUint32(info[0:], ethtoolGSsetInfo)
+	binary.NativeEndian.PutUint64(info[8:], 1<<ethSSStats)
+	if err := s.ioctl(iface, info); err != nil {
+		return 0, err
+	}
+	if binary.NativeEndian.Uint64(info[8:])&(1<<ethSSStats) == 0 {
+		return 0, nil
+	}
+	return binary.NativeEndian.Uint32(info[16:]), nil
+}
+
+// EthtoolCollector collects driver-level NIC statistics via the ethtool ioctl API.
+type EthtoolCollector struct {
+	statDesc         *prometheus.Desc
+	queueStatDesc    *prometheus.Desc
+	priorityStatDesc *prometheus.Desc
+
+	interfaceInclude *regexp.Regexp
+	interfaceExclude *regexp.Regexp
+	statInclude      *regexp.Regexp
+}
+
+// NewEthtoolCollector creates a new EthtoolCollector.
+// Interfaces are filtered like in NetworkCollector; statInclude restricts the
+// exported statistics by their ethtool name. A nil regexp disables the respective filter.
+func NewEthtoolCollector(interfaceInclude, interfaceExclude, statInclude *regexp.Regexp) *EthtoolCollector {
+	return &EthtoolCollector{
+		statDesc: prometheus.NewDesc(
+			"ethtool_stat",
+			"Driver statistic of network interface as reported by ethtool -S",
+			[]string{"interface", "stat"}, nil,
+		),
+		queueStatDesc: prometheus.NewDesc(
+			"ethtool_queue_stat",
+			"Per-queue driver statistic of network interface as reported by ethtool -S",
+			[]string{"interface", "direction", "queue", "stat"}, nil,
+		),
+		priorityStatDesc: prometheus.NewDesc(
+			"ethtool_priority_stat",
+			"Per-priority (PFC) driver statistic of network interface as reported by ethtool -S",
+			[]string{"interface", "direction", "priority", "stat"}, nil,
+		),
+		interfaceInclude: interfaceInclude,
+		interfaceExclude: interfaceExclude,
+		statInclude:      statInclude,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *EthtoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.statDesc
+	ch <- c.queueStatDesc
+	ch <- c.priorityStatDesc
+}
+
+// Collect queries driver statistics of every monitored interface and sends them to the channel.
+// Interfaces whose driver does not support statistics (e.g. bridges, veth) are skipped.
+func (c *EthtoolCollector) Collect(ch chan<- prometheus.Metric) {
+	sock, err := openEthtoolSocket()
+	if err != nil {
+		return
+	}
+	defer sock.Close()
+
+	for _, iface := range listInterfaces(c.interfaceInclude, c.interfaceExclude) {
+		names, values, err := sock.stats(iface)
+		if err != nil {
+			continue
+		}
+
+		for i, name := range names {
+			if c.statInclude != nil && !c.statInclude.MatchString(name) {
+				continue
+			}
+			v := float64(values[i])
+
+			if m := ethtoolPriorityStat.FindStringSubmatch(name); m != nil {
+				ch <- prometheus.MustNewConstMetric(c.priorityStatDesc, prometheus.UntypedValue, v, iface, m[1], m[2], m[3])
+			} else if m := ethtoolQueueStat.FindStringSubmatch(name); m != nil {
+				ch <- prometheus.MustNewConstMetric(c.queueStatDesc, prometheus.UntypedValue, v, iface, m[1], m[2], m[3])
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.statDesc, prometheus.UntypedValue, v, iface, name)
+			}
+		}
+	}
+}
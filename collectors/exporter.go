@@ -0,0 +1,172 @@
+package collectors
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// InstrumentedCollector wraps a collector and reports how long each collection
+// took and whether it succeeded.
+//
+// A collection fails if the wrapped collector panics or sends an invalid metric
+// (see prometheus.NewInvalidMetric), which is how collectors report that they
+// could not read their data source; collectors whose sources are optional skip
+// them without failing. Invalid metrics are logged and dropped, so a failing
+// collector does not fail the whole scrape.
+//
+// A collection that does not finish within the timeout fails as well: the
+// metrics sent so far are kept and the straggler is abandoned. Collectors that
+// implement ContextCollector are cancelled, which kills the commands they run;
+// others keep running in the background. Until the straggler returns, later
+// scrapes do not start the collector again and report it as failed.
+type InstrumentedCollector struct {
+	durationDesc *prometheus.Desc
+	successDesc  *prometheus.Desc
+
+	name      string
+	collector prometheus.Collector
+	unchecked bool
+	timeout   time.Duration
+
+	mu      sync.Mutex
+	running bool
+}
+
+// ContextCollector is a collector that stops collecting when ctx is done, e.g.
+// one that runs external commands with exec.CommandContext.
+type ContextCollector interface {
+	prometheus.Collector
+	CollectContext(ctx context.Context, ch chan<- prometheus.Metric)
+}
+
+// NewInstrumentedCollector creates a new InstrumentedCollector for the collector
+// registered under name. A zero timeout lets collections run indefinitely.
+func NewInstrumentedCollector(name string, collector prometheus.Collector, timeout time.Duration) *InstrumentedCollector {
+	labels := prometheus.Labels{"collector": name}
+
+	// A collector that describes nothing is unchecked; the wrapper must be too,
+	// or the registry would reject the metrics it collects.
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		collector.Describe(descs)
+		close(descs)
+	}()
+	unchecked := true
+	for range descs {
+		unchecked = false
+	}
+
+	return &InstrumentedCollector{
+		durationDesc: prometheus.NewDesc(
+			"exporter_collector_duration_seconds",
+			"Time the collector took to collect its metrics in the last scrape in seconds",
+			nil, labels,
+		),
+		successDesc: prometheus.NewDesc(
+			"exporter_collector_success",
+			"Whether the collector succeeded in the last scrape (0 = panicked, timed out, or reported an invalid metric)",
+			nil, labels,
+		),
+		name:      name,
+		collector: collector,
+		unchecked: unchecked,
+		timeout:   timeout,
+	}
+}
+
+// Describe sends the descriptors of the wrapped collector and the
+// instrumentation metrics to the channel. Nothing is sent for an unchecked
+// collector.
+func (c *InstrumentedCollector) Describe(ch chan<- *prometheus.Desc) {
+	if c.unchecked {
+		return
+	}
+	c.collector.Describe(ch)
+	ch <- c.durationDesc
+	ch <- c.successDesc
+}
+
+// Collect runs the wrapped collector, forwarding its valid metrics to the
+// channel until it returns or times out, and then sends its duration and success.
+func (c *InstrumentedCollector) Collect(ch chan<- prometheus.Metric) {
+	start := time.Now()
+	ok := c.collect(ch)
+	ch <- prometheus.MustNewConstMetric(c.durationDesc, prometheus.GaugeValue, time.Since(start).Seconds())
+	ch <- prometheus.MustNewConstMetric(c.successDesc, prometheus.GaugeValue, boolToFloat(ok))
+}
+
+// collect forwards the metrics of one collection of the wrapped collector to ch
+// and reports whether it succeeded within the timeout.
+func (c *InstrumentedCollector) collect(ch chan<- prometheus.Metric) bool {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		log.Printf("%s collector: previous collection still running, skipping", c.name)
+		return false
+	}
+	c.running = true
+	c.mu.Unlock()
+
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	metrics := make(chan prometheus.Metric)
+	returned := make(chan bool, 1)
+	go func() {
+		returned <- c.run(ctx, metrics)
+		close(metrics)
+		c.mu.Lock()
+		c.running = false
+		c.mu.Unlock()
+	}()
+
+	ok := true
+	for {
+		select {
+		case m, open := <-metrics:
+			if !open {
+				return <-returned && ok
+			}
+			if err := m.Write(&dto.Metric{}); err != nil {
+				log.Printf("%s collector: %v", c.name, err)
+				ok = false
+				continue
+			}
+			ch <- m
+		case <-ctx.Done():
+			log.Printf("%s collector: timed out after %v", c.name, c.timeout)
+			// ch must not be used once Collect returns; discard the rest
+			go func() {
+				for range metrics {
+				}
+			}()
+			return false
+		}
+	}
+}
+
+// run collects the wrapped collector into ch, cancelled by ctx if it is a
+// ContextCollector, and reports whether it returned without panicking.
+func (c *InstrumentedCollector) run(ctx context.Context, ch chan<- prometheus.Metric) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%s collector panicked: %v", c.name, r)
+			ok = false
+		}
+	}()
+	if cc, isContext := c.collector.(ContextCollector); isContext {
+		cc.CollectContext(ctx, ch)
+	} else {
+		c.collector.Collect(ch)
+	}
+	return true
+}
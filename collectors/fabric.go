@@ -0,0 +1,113 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fabricManagerProcess is the process name of the NVIDIA fabric manager daemon.
+const fabricManagerProcess = "nv-fabricmanager"
+
+// FabricCollector collects the health of the NVIDIA fabric manager and the NVLink
+// fabric registration state of each GPU.
+type FabricCollector struct {
+	managerUpDesc *prometheus.Desc
+	stateDesc     *prometheus.Desc
+	healthyDesc   *prometheus.Desc
+}
+
+// NewFabricCollector creates a new FabricCollector.
+func NewFabricCollector() *FabricCollector {
+	return &FabricCollector{
+		managerUpDesc: prometheus.NewDesc(
+			"fabricmanager_up",
+			"Whether the nv-fabricmanager daemon is running (1 = running)",
+			nil, nil,
+		),
+		stateDesc: prometheus.NewDesc(
+			"gpu_fabric_info",
+			"NVLink fabric registration state of the GPU, always 1",
+			[]string{"gpu", "state", "status"}, nil,
+		),
+		healthyDesc: prometheus.NewDesc(
+			"gpu_fabric_healthy",
+			"Whether the GPU completed fabric registration successfully (1 = healthy)",
+			[]string{"gpu"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *FabricCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.managerUpDesc
+	ch <- c.stateDesc
+	ch <- c.healthyDesc
+}
+
+// Collect checks for a running fabric manager and queries the fabric state of the
+// GPUs with nvidia-smi, sending both to the channel. GPUs without fabric support
+// (no NVSwitch or multi-node NVLink) are omitted.
+func (c *FabricCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing nvidia-smi when ctx is done.
+func (c *FabricCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	up := 0.0
+	if processRunning(fabricManagerProcess) {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.managerUpDesc, prometheus.GaugeValue, up)
+
+	out, err := exec.CommandContext(
+		ctx,
+		"nvidia-smi",
+		"--query-gpu=pci.bus_id,fabric.state,fabric.status",
+		"--format=csv,noheader",
+	).Output()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.managerUpDesc, fmt.Errorf("nvidia-smi fabric query failed: %w", err))
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			continue
+		}
+		gpu := strings.TrimSpace(fields[0])
+		state := strings.TrimSpace(fields[1])
+		status := strings.TrimSpace(fields[2])
+		if state == "" || state == "N/A" || state == "[N/A]" || state == "Not Supported" || state == "[Not Supported]" {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.stateDesc, prometheus.GaugeValue, 1, gpu, state, status)
+		healthy := 0.0
+		if state == "Completed" && status == "Success" {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.healthyDesc, prometheus.GaugeValue, healthy, gpu)
+	}
+}
+
+// processRunning reports whether a process with the given command name exists.
+func processRunning(comm string) bool {
+	paths, _ := filepath.Glob("/proc/[0-9]*/comm")
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(data)) == comm {
+			return true
+		}
+	}
+	return false
+}
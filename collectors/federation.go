@@ -0,0 +1,147 @@
+package collectors
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+)
+
+// FederationHeader marks scrapes made by a federating exporter. An exporter answers
+// them with its own metrics only, so two peers federating each other do not loop.
+const FederationHeader = "X-DGX-Spark-Federation"
+
+// FederationGatherer scrapes the /metrics endpoint of a peer exporter and returns
+// its metrics, labelled with the peer's host, to be served next to the local ones.
+type FederationGatherer struct {
+	url       string
+	localHost string
+	client    *http.Client
+}
+
+// NewFederationGatherer creates a new FederationGatherer scraping metricsURL with
+// the given timeout. localHost is the host label of the exporter's own metrics,
+// used for the federation_* metrics describing the peer scrape.
+func NewFederationGatherer(metricsURL, localHost string, timeout time.Duration) *FederationGatherer {
+	return &FederationGatherer{
+		url:       metricsURL,
+		localHost: localHost,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Gather scrapes the peer and returns its metric families, followed by
+// federation_up and federation_scrape_duration_seconds. Metrics without a host
+// label, such as the peer's go_* and process_* metrics, get the host label found
+// on the peer's other metrics, or the host name of the URL. A failed scrape is
+// logged and reported through federation_up only, so the local metrics are
+// still served.
+func (g *FederationGatherer) Gather() ([]*dto.MetricFamily, error) {
+	start := time.Now()
+	families, err := g.scrape()
+	if err != nil {
+		log.Printf("federation: scraping %s: %v", g.url, err)
+		families = nil
+	}
+
+	peerHost := ""
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "host" && peerHost == "" {
+					peerHost = l.GetValue()
+				}
+			}
+		}
+	}
+	if peerHost == "" {
+		if u, err := url.Parse(g.url); err == nil {
+			peerHost = u.Hostname()
+		}
+	}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			setHostLabel(m, peerHost)
+		}
+	}
+
+	up := 0.0
+	if err == nil {
+		up = 1
+	}
+	labels := []*dto.LabelPair{
+		{Name: proto.String("host"), Value: proto.String(g.localHost)},
+		{Name: proto.String("peer"), Value: proto.String(g.url)},
+	}
+	families = append(families,
+		&dto.MetricFamily{
+			Name:   proto.String("federation_up"),
+			Help:   proto.String("Whether the last scrape of the peer exporter succeeded (1 = success)"),
+			Type:   dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{Label: labels, Gauge: &dto.Gauge{Value: proto.Float64(up)}}},
+		},
+		&dto.MetricFamily{
+			Name:   proto.String("federation_scrape_duration_seconds"),
+			Help:   proto.String("Duration of the last scrape of the peer exporter in seconds"),
+			Type:   dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{Label: labels, Gauge: &dto.Gauge{Value: proto.Float64(time.Since(start).Seconds())}}},
+		},
+	)
+	return families, nil
+}
+
+// scrape fetches the peer's metrics, marking the request as a federation scrape.
+func (g *FederationGatherer) scrape() ([]*dto.MetricFamily, error) {
+	req, err := http.NewRequest(http.MethodGet, g.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(FederationHeader, "1")
+	return fetchMetricFamilies(g.client, req)
+}
+
+// fetchMetricFamilies sends req to a Prometheus metrics endpoint and decodes the
+// response, preferring the protobuf format.
+func fetchMetricFamilies(client *http.Client, req *http.Request) ([]*dto.MetricFamily, error) {
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeProtoDelim))+`;q=0.7,text/plain;version=0.0.4;q=0.3`)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var families []*dto.MetricFamily
+	dec := expfmt.NewDecoder(resp.Body, expfmt.ResponseFormat(resp.Header))
+	for {
+		mf := &dto.MetricFamily{}
+		if err := dec.Decode(mf); err == io.EOF {
+			return families, nil
+		} else if err != nil {
+			return nil, err
+		}
+		families = append(families, mf)
+	}
+}
+
+// setHostLabel adds a host label to m unless it already has one, keeping the
+// labels sorted by name.
+func setHostLabel(m *dto.Metric, host string) {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == "host" {
+			return
+		}
+	}
+	m.Label = append(m.Label, &dto.LabelPair{Name: proto.String("host"), Value: proto.String(host)})
+	sort.Slice(m.Label, func(i, j int) bool { return m.Label[i].GetName() < m.Label[j].GetName() })
+}
@@ -0,0 +1,56 @@
+package collectors
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// FileFDCollector collects system-wide file descriptor usage.
+type FileFDCollector struct {
+	allocatedDesc *prometheus.Desc
+	maximumDesc   *prometheus.Desc
+}
+
+// NewFileFDCollector creates a new FileFDCollector.
+func NewFileFDCollector() *FileFDCollector {
+	return &FileFDCollector{
+		allocatedDesc: prometheus.NewDesc(
+			"node_filefd_allocated",
+			"Number of file handles allocated by the kernel",
+			nil, nil,
+		),
+		maximumDesc: prometheus.NewDesc(
+			"node_filefd_maximum",
+			"Maximum number of file handles the kernel will allocate (fs.file-max)",
+			nil, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *FileFDCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.allocatedDesc
+	ch <- c.maximumDesc
+}
+
+// Collect reads /proc/sys/fs/file-nr and sends the file handle usage to the channel.
+func (c *FileFDCollector) Collect(ch chan<- prometheus.Metric) {
+	data, err := os.ReadFile("/proc/sys/fs/file-nr")
+	if err != nil {
+		return
+	}
+	// Format: "<allocated> <allocated but unused (always 0)> <maximum>"
+	fields := strings.Fields(string(data))
+	if len(fields) != 3 {
+		return
+	}
+	if v, err := strconv.ParseFloat(fields[0], 64); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.allocatedDesc, prometheus.GaugeValue, v)
+	}
+	if v, err := strconv.ParseFloat(fields[2], 64); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.maximumDesc, prometheus.GaugeValue, v)
+	}
+}
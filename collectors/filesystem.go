@@ -0,0 +1,274 @@
+package collectors
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultFilesystemMountExclude matches mountpoints of pseudo and container filesystems.
+const DefaultFilesystemMountExclude = `^/(dev|proc|run/credentials/.+|sys|var/lib/docker/.+|var/lib/containers/storage/.+)($|/)`
+
+// DefaultFilesystemFSTypeExclude matches filesystem types that do not represent real
+// local storage. Network and FUSE filesystems are excluded as well, since statfs on
+// an unreachable server or a stuck FUSE daemon blocks.
+const DefaultFilesystemFSTypeExclude = `^(9p|afs|autofs|binfmt_misc|bpf|ceph|cgroup2?|cifs|configfs|debugfs|devpts|devtmpfs|efivarfs|fuse\..+|fusectl|glusterfs|hugetlbfs|iso9660|lustre|mqueue|nfs4?|nsfs|overlay|proc|procfs|pstore|ramfs|rpc_pipefs|securityfs|selinuxfs|smb3|squashfs|erofs|sysfs|tmpfs|tracefs)$`
+
+// filesystemStatfsTimeout bounds the statfs of a single mount. A mount that does
+// not answer in time is skipped until its statfs returns.
+const filesystemStatfsTimeout = 2 * time.Second
+
+// mount is a single entry of /proc/mounts.
+type mount struct {
+	device     string
+	mountpoint string
+	fstype     string
+	options    string
+}
+
+// FilesystemCollector collects capacity and inode metrics for every mounted filesystem.
+type FilesystemCollector struct {
+	sizeDesc  *prometheus.Desc
+	freeDesc  *prometheus.Desc
+	usedDesc  *prometheus.Desc
+	availDesc *prometheus.Desc
+	filesDesc *prometheus.Desc
+	ffreeDesc *prometheus.Desc
+	roDesc    *prometheus.Desc
+	mountDesc *prometheus.Desc
+
+	mountExclude  *regexp.Regexp
+	fstypeExclude *regexp.Regexp
+
+	mu    sync.Mutex
+	stuck map[string]bool // mountpoints whose statfs has not returned
+}
+
+// NewFilesystemCollector creates a new FilesystemCollector.
+// Mounts whose mountpoint matches mountExclude or whose type matches fstypeExclude are skipped;
+// a nil regexp disables the respective filter.
+func NewFilesystemCollector(mountExclude, fstypeExclude *regexp.Regexp) *FilesystemCollector {
+	labels := []string{"device", "mountpoint", "fstype"}
+	return &FilesystemCollector{
+		sizeDesc: prometheus.NewDesc(
+			"filesystem_size_bytes",
+			"Filesystem size in bytes",
+			labels, nil,
+		),
+		freeDesc: prometheus.NewDesc(
+			"filesystem_free_bytes",
+			"Filesystem free space in bytes, including space reserved for root",
+			labels, nil,
+		),
+		usedDesc: prometheus.NewDesc(
+			"filesystem_used_bytes",
+			"Filesystem space in use in bytes, i.e. size minus free space (as df's Used)",
+			labels, nil,
+		),
+		availDesc: prometheus.NewDesc(
+			"filesystem_avail_bytes",
+			"Filesystem space available to non-root users in bytes",
+			labels, nil,
+		),
+		filesDesc: prometheus.NewDesc(
+			"filesystem_files",
+			"Total number of inodes on the filesystem",
+			labels, nil,
+		),
+		ffreeDesc: prometheus.NewDesc(
+			"filesystem_files_free",
+			"Number of free inodes on the filesystem",
+			labels, nil,
+		),
+		roDesc: prometheus.NewDesc(
+			"filesystem_readonly",
+			"Whether the filesystem is mounted read-only (1 = read-only, 0 = read-write)",
+			labels, nil,
+		),
+		mountDesc: prometheus.NewDesc(
+			"mount_info",
+			"Mount configuration from /proc/mounts (always 1)",
+			append(labels, "options"), nil,
+		),
+		mountExclude:  mountExclude,
+		fstypeExclude: fstypeExclude,
+		stuck:         make(map[string]bool),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *FilesystemCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sizeDesc
+	ch <- c.freeDesc
+	ch <- c.usedDesc
+	ch <- c.availDesc
+	ch <- c.filesDesc
+	ch <- c.ffreeDesc
+	ch <- c.roDesc
+	ch <- c.mountDesc
+}
+
+// Collect statfs's every non-excluded mountpoint and sends mount options, capacity,
+// inode, and read-only state metrics to the channel. A mount whose statfs hangs
+// gets no capacity metrics and fails the collection.
+func (c *FilesystemCollector) Collect(ch chan<- prometheus.Metric) {
+	mounts, err := readMounts()
+	if err != nil {
+		return
+	}
+
+	for _, m := range mounts {
+		if c.mountExclude != nil && c.mountExclude.MatchString(m.mountpoint) {
+			continue
+		}
+		if c.fstypeExclude != nil && c.fstypeExclude.MatchString(m.fstype) {
+			continue
+		}
+
+		labels := []string{m.device, m.mountpoint, m.fstype}
+
+		ch <- prometheus.MustNewConstMetric(c.mountDesc, prometheus.GaugeValue, 1, append(labels, m.options)...)
+
+		// Reported even if statfs fails, e.g. when ext4 remounted read-only after an error
+		readonly := 0.0
+		if hasMountOption(m.options, "ro") {
+			readonly = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.roDesc, prometheus.GaugeValue, readonly, labels...)
+
+		stat, err := c.statfs(m.mountpoint)
+		if err != nil {
+			if errors.Is(err, errStatfsTimeout) {
+				ch <- prometheus.NewInvalidMetric(c.sizeDesc, err)
+			}
+			continue
+		}
+
+		bsize := float64(stat.Bsize)
+
+		ch <- prometheus.MustNewConstMetric(c.sizeDesc, prometheus.GaugeValue, float64(stat.Blocks)*bsize, labels...)
+		ch <- prometheus.MustNewConstMetric(c.freeDesc, prometheus.GaugeValue, float64(stat.Bfree)*bsize, labels...)
+		ch <- prometheus.MustNewConstMetric(c.usedDesc, prometheus.GaugeValue, float64(stat.Blocks-stat.Bfree)*bsize, labels...)
+		ch <- prometheus.MustNewConstMetric(c.availDesc, prometheus.GaugeValue, float64(stat.Bavail)*bsize, labels...)
+
+		// Some filesystems (e.g. btrfs, vfat) have no fixed inode table and report 0
+		if stat.Files > 0 {
+			ch <- prometheus.MustNewConstMetric(c.filesDesc, prometheus.GaugeValue, float64(stat.Files), labels...)
+			ch <- prometheus.MustNewConstMetric(c.ffreeDesc, prometheus.GaugeValue, float64(stat.Ffree), labels...)
+		}
+	}
+}
+
+// errStatfsTimeout is returned by statfs for a mount that does not answer.
+var errStatfsTimeout = errors.New("statfs timed out")
+
+// statfs runs statfs on mountpoint, giving up after filesystemStatfsTimeout. The
+// call cannot be interrupted, so it is left running and the mount is skipped
+// until it returns.
+func (c *FilesystemCollector) statfs(mountpoint string) (*syscall.Statfs_t, error) {
+	c.mu.Lock()
+	if c.stuck[mountpoint] {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%s: %w earlier and has not returned", mountpoint, errStatfsTimeout)
+	}
+	c.stuck[mountpoint] = true
+	c.mu.Unlock()
+
+	type result struct {
+		stat syscall.Statfs_t
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		r.err = syscall.Statfs(mountpoint, &r.stat)
+		c.mu.Lock()
+		delete(c.stuck, mountpoint)
+		c.mu.Unlock()
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		return &r.stat, r.err
+	case <-time.After(filesystemStatfsTimeout):
+		return nil, fmt.Errorf("%s: %w after %v", mountpoint, errStatfsTimeout, filesystemStatfsTimeout)
+	}
+}
+
+// readMounts parses /proc/mounts. When the same mountpoint is mounted more than
+// once (e.g. over-mounts), only the last and therefore visible entry is returned.
+func readMounts() ([]mount, error) {
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []mount
+	seen := make(map[string]int)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: device mountpoint fstype options dump pass
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		m := mount{
+			device:     unescapeMountField(fields[0]),
+			mountpoint: unescapeMountField(fields[1]),
+			fstype:     fields[2],
+			options:    fields[3],
+		}
+
+		if i, ok := seen[m.mountpoint]; ok {
+			mounts[i] = m
+			continue
+		}
+		seen[m.mountpoint] = len(mounts)
+		mounts = append(mounts, m)
+	}
+
+	return mounts, scanner.Err()
+}
+
+// hasMountOption reports whether the comma-separated mount options contain option.
+func hasMountOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// unescapeMountField decodes the octal escapes (e.g. \040 for space) used in /proc/mounts.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
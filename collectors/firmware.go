@@ -0,0 +1,103 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Firmware version sources.
+const (
+	esrtEntriesDir   = "/sys/firmware/efi/esrt/entries"
+	nvidiaGPUProcDir = "/proc/driver/nvidia/gpus"
+)
+
+// FirmwareCollector exports the system firmware (UEFI/BIOS) version and the
+// versions of updatable firmware components.
+type FirmwareCollector struct {
+	infoDesc          *prometheus.Desc
+	componentInfoDesc *prometheus.Desc
+}
+
+// NewFirmwareCollector creates a new FirmwareCollector.
+func NewFirmwareCollector() *FirmwareCollector {
+	return &FirmwareCollector{
+		infoDesc: prometheus.NewDesc(
+			"firmware_info",
+			"System firmware (UEFI/BIOS) version from SMBIOS, always 1",
+			[]string{"vendor", "version", "date", "release"}, nil,
+		),
+		componentInfoDesc: prometheus.NewDesc(
+			"firmware_component_info",
+			"Version of a firmware component, always 1 (component = esrt or gpu_vbios)",
+			[]string{"component", "id", "version"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *FirmwareCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.componentInfoDesc
+}
+
+// Collect reads the firmware versions and sends the info metrics to the channel.
+// Components are taken from the EFI System Resource Table (firmware updatable
+// through fwupd/capsules, keyed by firmware class GUID) and from the NVIDIA
+// driver (GPU video BIOS, keyed by PCI bus ID).
+func (c *FirmwareCollector) Collect(ch chan<- prometheus.Metric) {
+	version := readSysString(filepath.Join(dmiIDDir, "bios_version"))
+	if version != "" {
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1,
+			readSysString(filepath.Join(dmiIDDir, "bios_vendor")),
+			version,
+			readSysString(filepath.Join(dmiIDDir, "bios_date")),
+			readSysString(filepath.Join(dmiIDDir, "bios_release")),
+		)
+	}
+
+	entries, _ := os.ReadDir(esrtEntriesDir)
+	for _, e := range entries {
+		dir := filepath.Join(esrtEntriesDir, e.Name())
+		class := readSysString(filepath.Join(dir, "fw_class"))
+		fwVersion := readSysString(filepath.Join(dir, "fw_version"))
+		if class == "" || fwVersion == "" {
+			continue
+		}
+		// The version encoding is vendor-specific; export the raw decimal value
+		ch <- prometheus.MustNewConstMetric(c.componentInfoDesc, prometheus.GaugeValue, 1, "esrt", class, fwVersion)
+	}
+
+	gpus, _ := os.ReadDir(nvidiaGPUProcDir)
+	for _, g := range gpus {
+		vbios := readNvidiaGPUInformation(filepath.Join(nvidiaGPUProcDir, g.Name(), "information"))["Video BIOS"]
+		if vbios == "" || strings.Trim(vbios, "?") == "" {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.componentInfoDesc, prometheus.GaugeValue, 1, "gpu_vbios", g.Name(), vbios)
+	}
+}
+
+// readNvidiaGPUInformation parses the "Key: value" lines of
+// /proc/driver/nvidia/gpus/<bus>/information.
+func readNvidiaGPUInformation(path string) map[string]string {
+	info := make(map[string]string)
+	f, err := os.Open(path)
+	if err != nil {
+		return info
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		info[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return info
+}
@@ -0,0 +1,93 @@
+package collectors
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fsErrorPatterns match kernel log messages reporting filesystem errors and
+// capture the affected block device name.
+var fsErrorPatterns = []*regexp.Regexp{
+	// EXT4-fs error (device nvme0n1p2): ext4_lookup:1855: inode #2: comm ls: deleted inode referenced
+	regexp.MustCompile(`^EXT[234]-fs error \(device ([^)\s]+)\)`),
+	// XFS (dm-0): Corruption detected. Unmount and run xfs_repair
+	// XFS (sda1): metadata I/O error in "xfs_trans_read_buf_map" at daddr 0x2 len 1 error 5
+	regexp.MustCompile(`^XFS \(([^)\s]+)\): .*(?:[Cc]orrupt|I/O error|[Ee]rror)`),
+	// BTRFS error (device sda1 state EA): bdev /dev/sda1 errs: wr 1, rd 0, flush 0, corrupt 0, gen 0
+	regexp.MustCompile(`^BTRFS (?:error|critical) \(device ([^)\s]+)`),
+}
+
+// FilesystemErrorsCollector counts filesystem error messages in the kernel log.
+type FilesystemErrorsCollector struct {
+	errorsDesc *prometheus.Desc
+
+	mu     sync.Mutex
+	counts map[string]float64 // keyed by block device name, e.g. nvme0n1p2
+
+	kmsg      *kmsgFollower
+	followErr error // set if the kernel log cannot be followed
+}
+
+// NewFilesystemErrorsCollector creates a new FilesystemErrorsCollector and starts
+// following the kernel log. Errors already in the ring buffer are counted too, so
+// the counters cover the current boot as far back as the buffer reaches.
+func NewFilesystemErrorsCollector() *FilesystemErrorsCollector {
+	c := &FilesystemErrorsCollector{
+		errorsDesc: prometheus.NewDesc(
+			"filesystem_errors_total",
+			"Total number of filesystem error messages in the kernel log, by kernel block device name (e.g. nvme0n1p2, dm-0)",
+			[]string{"device"}, nil,
+		),
+		counts: make(map[string]float64),
+	}
+
+	kmsg, err := followKmsg(c.handleMessage)
+	if err != nil {
+		c.followErr = fmt.Errorf("cannot follow kernel log: %w", err)
+	}
+	c.kmsg = kmsg
+	return c
+}
+
+// handleMessage counts a kernel log message if it reports a filesystem error.
+func (c *FilesystemErrorsCollector) handleMessage(message string) {
+	for _, re := range fsErrorPatterns {
+		m := re.FindStringSubmatch(message)
+		if m == nil {
+			continue
+		}
+		c.mu.Lock()
+		c.counts[m[1]]++
+		c.mu.Unlock()
+		return
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *FilesystemErrorsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.errorsDesc
+}
+
+// Collect sends the filesystem error counts to the channel. They are labelled with
+// the device only, so a remount does not move a count to a new series; mount_info
+// maps devices to mountpoints. Series only appear once the first error for a
+// device has been seen.
+func (c *FilesystemErrorsCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.followErr != nil {
+		ch <- prometheus.NewInvalidMetric(c.errorsDesc, c.followErr)
+		return
+	}
+	if err := c.kmsg.Err(); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.errorsDesc, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for device, n := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.errorsDesc, prometheus.CounterValue, n, device)
+	}
+}
@@ -0,0 +1,47 @@
+package collectors
+
+import (
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultFstrimStampFile is the persistent stamp systemd updates whenever fstrim.timer elapses.
+const DefaultFstrimStampFile = "/var/lib/systemd/timers/stamp-fstrim.timer"
+
+// FstrimCollector reports when fstrim last ran, based on the modification time of a stamp file.
+type FstrimCollector struct {
+	stampFile string
+
+	lastRunDesc *prometheus.Desc
+}
+
+// NewFstrimCollector creates a new FstrimCollector reading the modification time of stampFile.
+// This is either the systemd fstrim.timer stamp or a state file touched after a successful
+// fstrim (e.g. from an ExecStartPost= drop-in).
+func NewFstrimCollector(stampFile string) *FstrimCollector {
+	return &FstrimCollector{
+		stampFile: stampFile,
+		lastRunDesc: prometheus.NewDesc(
+			"fstrim_last_run_timestamp_seconds",
+			"Unix timestamp of the last fstrim run, from the stamp file modification time",
+			nil, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *FstrimCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lastRunDesc
+}
+
+// Collect stats the stamp file and sends the last run timestamp to the channel.
+// If fstrim has never run (no stamp file), no metric is emitted.
+func (c *FstrimCollector) Collect(ch chan<- prometheus.Metric) {
+	info, err := os.Stat(c.stampFile)
+	if err != nil {
+		return
+	}
+	ts := float64(info.ModTime().UnixNano()) / 1e9
+	ch <- prometheus.MustNewConstMetric(c.lastRunDesc, prometheus.GaugeValue, ts)
+}
@@ -1,7 +1,8 @@
 package collectors
 
 import (
-	"log"
+	"context"
+	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -54,13 +55,19 @@ func (c *GPUCollector) Describe(ch chan<- *prometheus.Desc) {
 // Collect runs nvidia-smi and sends GPU metrics to the channel.
 // If nvidia-smi is not available or fails, no metrics are emitted.
 func (c *GPUCollector) Collect(ch chan<- prometheus.Metric) {
-	out, err := exec.Command(
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing nvidia-smi when ctx is done.
+func (c *GPUCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	out, err := exec.CommandContext(
+		ctx,
 		"nvidia-smi",
 		"--query-gpu=utilization.gpu,temperature.gpu,power.draw,clocks.current.graphics",
 		"--format=csv,noheader,nounits",
 	).Output()
 	if err != nil {
-		log.Printf("nvidia-smi failed: %v", err)
+		ch <- prometheus.NewInvalidMetric(c.utilizationDesc, fmt.Errorf("nvidia-smi failed: %w", err))
 		return
 	}
 
@@ -72,7 +79,7 @@ func (c *GPUCollector) Collect(ch chan<- prometheus.Metric) {
 
 	fields := strings.Split(lines[0], ",")
 	if len(fields) < 4 {
-		log.Printf("nvidia-smi: unexpected output format: %q", lines[0])
+		ch <- prometheus.NewInvalidMetric(c.utilizationDesc, fmt.Errorf("nvidia-smi: unexpected output format: %q", lines[0]))
 		return
 	}
 
@@ -0,0 +1,185 @@
+package collectors
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Severities of hardware errors.
+const (
+	hwErrorCorrected   = "corrected"
+	hwErrorUncorrected = "uncorrected"
+	hwErrorFatal       = "fatal"
+	hwErrorUnknown     = "unknown"
+)
+
+// hwErrorKey identifies a hardware error counter.
+type hwErrorKey struct {
+	errType, severity string
+}
+
+// hwErrorPattern matches a kernel log message reporting a hardware error and maps
+// it to a counter key and an error count.
+type hwErrorPattern struct {
+	re    *regexp.Regexp
+	match func(m []string) (hwErrorKey, float64)
+}
+
+// hwErrorPatterns match kernel log messages reporting hardware errors. Each error
+// is counted once: multi-line reports are matched on a single summary line.
+var hwErrorPatterns = []hwErrorPattern{
+	{
+		// EDAC MC0: 1 CE memory read error on CPU_SrcID#0_Ha#0_Chan#0_DIMM#0 (...)
+		// EDAC MC0: 2 UE memory scrubbing error on ...
+		re: regexp.MustCompile(`^EDAC \S+: (\d+) (CE|UE) `),
+		match: func(m []string) (hwErrorKey, float64) {
+			n, _ := strconv.ParseFloat(m[1], 64)
+			severity := hwErrorCorrected
+			if m[2] == "UE" {
+				severity = hwErrorUncorrected
+			}
+			return hwErrorKey{"memory", severity}, n
+		},
+	},
+	{
+		// pcieport 0000:00:01.0: PCIe Bus Error: severity=Corrected, type=Physical Layer, (Receiver ID)
+		// pcieport 0000:00:01.0: PCIe Bus Error: severity=Uncorrectable (Fatal), type=Transaction Layer, (Requester ID)
+		re: regexp.MustCompile(`PCIe Bus Error: severity=([^,]+),`),
+		match: func(m []string) (hwErrorKey, float64) {
+			return hwErrorKey{"pcie", aerSeverity(m[1])}, 1
+		},
+	},
+	{
+		// {1}[Hardware Error]: event severity: corrected
+		re: regexp.MustCompile(`\[Hardware Error\]: event severity: (\w+)`),
+		match: func(m []string) (hwErrorKey, float64) {
+			severity := m[1]
+			if severity == "recoverable" {
+				severity = hwErrorUncorrected
+			}
+			return hwErrorKey{"apei", severity}, 1
+		},
+	},
+	{
+		// mce: [Hardware Error]: Machine check events logged
+		re: regexp.MustCompile(`^mce: \[Hardware Error\]: Machine check events logged`),
+		match: func(m []string) (hwErrorKey, float64) {
+			return hwErrorKey{"mce", hwErrorUnknown}, 1
+		},
+	},
+	{
+		// SError Interrupt on CPU3, code 0xbe000011 -- SError
+		re: regexp.MustCompile(`^SError Interrupt on CPU\d+`),
+		match: func(m []string) (hwErrorKey, float64) {
+			return hwErrorKey{"serror", hwErrorFatal}, 1
+		},
+	},
+}
+
+// hwErrorKnownKeys are reported as zero before the first error of their kind, so
+// that increase() catches the first occurrence.
+var hwErrorKnownKeys = []hwErrorKey{
+	{"memory", hwErrorCorrected},
+	{"memory", hwErrorUncorrected},
+	{"pcie", hwErrorCorrected},
+	{"pcie", hwErrorUncorrected},
+	{"pcie", hwErrorFatal},
+	{"apei", hwErrorCorrected},
+	{"apei", hwErrorUncorrected},
+	{"apei", hwErrorFatal},
+	{"serror", hwErrorFatal},
+}
+
+// aerSeverity maps a PCIe AER severity string to a counter severity. Kernels
+// before 6.9 print "Corrected" and "Uncorrected (...)" instead of "Correctable"
+// and "Uncorrectable (...)".
+func aerSeverity(s string) string {
+	switch {
+	case strings.HasPrefix(s, "Correct"):
+		return hwErrorCorrected
+	case strings.Contains(s, "Non-Fatal"):
+		return hwErrorUncorrected
+	case strings.Contains(s, "Fatal"):
+		return hwErrorFatal
+	}
+	return hwErrorUnknown
+}
+
+// HardwareErrorsCollector counts machine check, SError, APEI, PCIe AER, and EDAC
+// memory errors reported in the kernel log.
+type HardwareErrorsCollector struct {
+	errorsDesc *prometheus.Desc
+
+	mu     sync.Mutex
+	counts map[hwErrorKey]float64
+
+	kmsg      *kmsgFollower
+	followErr error // set if the kernel log cannot be followed
+}
+
+// NewHardwareErrorsCollector creates a new HardwareErrorsCollector and starts
+// following the kernel log. Errors already in the ring buffer are counted too, so
+// the counters cover the current boot as far back as the buffer reaches.
+func NewHardwareErrorsCollector() *HardwareErrorsCollector {
+	c := &HardwareErrorsCollector{
+		errorsDesc: prometheus.NewDesc(
+			"hardware_errors_total",
+			"Total number of hardware errors reported in the kernel log",
+			[]string{"type", "severity"}, nil,
+		),
+		counts: make(map[hwErrorKey]float64),
+	}
+	for _, key := range hwErrorKnownKeys {
+		c.counts[key] = 0
+	}
+
+	kmsg, err := followKmsg(c.handleMessage)
+	if err != nil {
+		c.followErr = fmt.Errorf("cannot follow kernel log: %w", err)
+	}
+	c.kmsg = kmsg
+	return c
+}
+
+// handleMessage counts a kernel log message if it reports a hardware error.
+func (c *HardwareErrorsCollector) handleMessage(message string) {
+	for _, p := range hwErrorPatterns {
+		m := p.re.FindStringSubmatch(message)
+		if m == nil {
+			continue
+		}
+		key, n := p.match(m)
+		c.mu.Lock()
+		c.counts[key] += n
+		c.mu.Unlock()
+		return
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *HardwareErrorsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.errorsDesc
+}
+
+// Collect sends the hardware error counts to the channel.
+func (c *HardwareErrorsCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.followErr != nil {
+		ch <- prometheus.NewInvalidMetric(c.errorsDesc, c.followErr)
+		return
+	}
+	if err := c.kmsg.Err(); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.errorsDesc, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, n := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.errorsDesc, prometheus.CounterValue, n, key.errType, key.severity)
+	}
+}
@@ -0,0 +1,99 @@
+package collectors
+
+import (
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// hwmonSensorType maps a hwmon sensor attribute to an exported gauge.
+type hwmonSensorType struct {
+	prefix string  // attribute prefix, e.g. "temp" for temp1_input
+	suffix string  // attribute suffix, e.g. "_input"
+	scale  float64 // multiplier converting the sysfs unit to the base unit
+	desc   *prometheus.Desc
+}
+
+// HwmonCollector collects all temperature, fan, voltage, current, and power inputs,
+// fan targets, and PWM outputs of every hwmon chip.
+type HwmonCollector struct {
+	types []hwmonSensorType
+}
+
+// NewHwmonCollector creates a new HwmonCollector.
+func NewHwmonCollector() *HwmonCollector {
+	labels := []string{"chip", "device", "sensor"}
+	gauge := func(prefix, suffix string, scale float64, name, help string) hwmonSensorType {
+		return hwmonSensorType{
+			prefix: prefix,
+			suffix: suffix,
+			scale:  scale,
+			desc:   prometheus.NewDesc(name, help, labels, nil),
+		}
+	}
+
+	return &HwmonCollector{
+		types: []hwmonSensorType{
+			gauge("temp", "_input", 0.001, "hwmon_temperature_celsius",
+				"Temperature reported by the hwmon sensor in degrees Celsius"),
+			gauge("fan", "_input", 1, "hwmon_fan_rpm",
+				"Fan speed reported by the hwmon sensor in revolutions per minute"),
+			gauge("fan", "_target", 1, "hwmon_fan_target_rpm",
+				"Fan speed requested from the fan controller in revolutions per minute"),
+			gauge("pwm", "", 1.0/255, "hwmon_pwm_duty_cycle_ratio",
+				"PWM duty cycle of the fan output (0-1)"),
+			gauge("pwm", "_enable", 1, "hwmon_pwm_mode",
+				"Control mode of the PWM output (0 = off or full speed, 1 = manual, 2 and above = automatic, driver-specific)"),
+			gauge("in", "_input", 0.001, "hwmon_voltage_volts",
+				"Voltage reported by the hwmon sensor in volts"),
+			gauge("curr", "_input", 0.001, "hwmon_current_amperes",
+				"Current reported by the hwmon sensor in amperes"),
+			gauge("power", "_input", 0.000001, "hwmon_power_watts",
+				"Power reported by the hwmon sensor in watts"),
+		},
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *HwmonCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, t := range c.types {
+		ch <- t.desc
+	}
+}
+
+// Collect reads /sys/class/hwmon and sends every sensor input to the channel.
+// Chips are identified by driver name and the device they belong to (e.g. the
+// I2C address), since hwmon<N> numbering is not stable across boots; chips without
+// a distinguishing device fall back to hwmon<N>.
+func (c *HwmonCollector) Collect(ch chan<- prometheus.Metric) {
+	chips, err := filepath.Glob("/sys/class/hwmon/hwmon*")
+	if err != nil {
+		return
+	}
+
+	type chipID struct{ name, device string }
+	seenChips := make(map[chipID]bool)
+
+	for _, chip := range chips {
+		id := chipID{readSysString(filepath.Join(chip, "name")), hwmonDeviceName(chip)}
+		if id.name == "" {
+			id.name = filepath.Base(chip)
+		}
+		if seenChips[id] {
+			id.device = filepath.Base(chip)
+		}
+		seenChips[id] = true
+
+		for _, t := range c.types {
+			// Labels are not guaranteed to be unique within a chip
+			seenLabels := make(map[string]bool)
+			for _, s := range readHwmonAttributes(chip, t.prefix, t.suffix) {
+				if seenLabels[s.label] {
+					s.label = s.name
+				}
+				seenLabels[s.label] = true
+				ch <- prometheus.MustNewConstMetric(t.desc, prometheus.GaugeValue, s.value*t.scale, id.name, id.device, s.label)
+			}
+		}
+	}
+}
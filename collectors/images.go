@@ -0,0 +1,139 @@
+package collectors
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultContainerImageInclude matches images from the NGC registry and the nvidia
+// namespace on Docker Hub.
+const DefaultContainerImageInclude = `^(nvcr\.io/|nvidia/)`
+
+// containerImage is one repository:tag of a locally pulled image.
+type containerImage struct {
+	repository, tag string
+	size            float64
+	created         float64
+}
+
+// ContainerImageCollector exports the locally pulled container images matching a
+// repository filter, so the image inventory of a fleet can be managed centrally.
+type ContainerImageCollector struct {
+	sizeDesc      *prometheus.Desc
+	createdDesc   *prometheus.Desc
+	countDesc     *prometheus.Desc
+	totalSizeDesc *prometheus.Desc
+	truncatedDesc *prometheus.Desc
+
+	include   *regexp.Regexp
+	maxImages int
+	client    *http.Client
+}
+
+// NewContainerImageCollector creates a new ContainerImageCollector listing the
+// images of the Docker daemon on socket whose repository matches include (nil =
+// all). At most maxImages images, the largest first, are reported individually.
+func NewContainerImageCollector(socket string, include *regexp.Regexp, maxImages int) *ContainerImageCollector {
+	labels := []string{"repository", "tag"}
+	return &ContainerImageCollector{
+		sizeDesc: prometheus.NewDesc(
+			"container_image_size_bytes",
+			"Size of the locally pulled container image in bytes, including layers shared with other images",
+			labels, nil,
+		),
+		createdDesc: prometheus.NewDesc(
+			"container_image_created_timestamp_seconds",
+			"Unix timestamp of the creation of the container image",
+			labels, nil,
+		),
+		countDesc: prometheus.NewDesc(
+			"container_images",
+			"Number of locally pulled container image tags matching the filter",
+			nil, nil,
+		),
+		totalSizeDesc: prometheus.NewDesc(
+			"container_images_size_bytes",
+			"Total size of the distinct container images matching the filter in bytes",
+			nil, nil,
+		),
+		truncatedDesc: prometheus.NewDesc(
+			"container_images_truncated",
+			"Whether more images matched than are reported individually (1 = truncated)",
+			nil, nil,
+		),
+		include:   include,
+		maxImages: maxImages,
+		client:    newDockerClient(socket),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ContainerImageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sizeDesc
+	ch <- c.createdDesc
+	ch <- c.countDesc
+	ch <- c.totalSizeDesc
+	ch <- c.truncatedDesc
+}
+
+// Collect lists the images of the Docker daemon and sends the matching ones to the
+// channel. An image with several tags is reported once per tag; untagged
+// (dangling) images are skipped. If the daemon is unreachable, no metrics are
+// emitted.
+func (c *ContainerImageCollector) Collect(ch chan<- prometheus.Metric) {
+	var list []struct {
+		ID       string   `json:"Id"`
+		RepoTags []string `json:"RepoTags"`
+		Size     float64  `json:"Size"`
+		Created  float64  `json:"Created"`
+	}
+	if err := getDocker(c.client, "/images/json", &list); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.sizeDesc, err)
+		return
+	}
+
+	var images []containerImage
+	totalSize := 0.0
+	for _, img := range list {
+		matched := false
+		for _, repoTag := range img.RepoTags {
+			// The repository may contain a registry port, so split at the last colon
+			i := strings.LastIndex(repoTag, ":")
+			if i < 0 || repoTag == "<none>:<none>" {
+				continue
+			}
+			repository, tag := repoTag[:i], repoTag[i+1:]
+			if c.include != nil && !c.include.MatchString(repository) {
+				continue
+			}
+			images = append(images, containerImage{repository, tag, img.Size, img.Created})
+			matched = true
+		}
+		if matched {
+			totalSize += img.Size
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.countDesc, prometheus.GaugeValue, float64(len(images)))
+	ch <- prometheus.MustNewConstMetric(c.totalSizeDesc, prometheus.GaugeValue, totalSize)
+	truncated := len(images) > c.maxImages
+	ch <- prometheus.MustNewConstMetric(c.truncatedDesc, prometheus.GaugeValue, boolToFloat(truncated))
+
+	if truncated {
+		sort.Slice(images, func(i, j int) bool {
+			if images[i].size != images[j].size {
+				return images[i].size > images[j].size
+			}
+			return images[i].repository+":"+images[i].tag < images[j].repository+":"+images[j].tag
+		})
+		images = images[:c.maxImages]
+	}
+	for _, img := range images {
+		ch <- prometheus.MustNewConstMetric(c.sizeDesc, prometheus.GaugeValue, img.size, img.repository, img.tag)
+		ch <- prometheus.MustNewConstMetric(c.createdDesc, prometheus.GaugeValue, img.created, img.repository, img.tag)
+	}
+}
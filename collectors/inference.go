@@ -0,0 +1,169 @@
+package collectors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultInferenceServers are the default ports of Ollama, vLLM, and Text
+// Generation Inference on the local host.
+const DefaultInferenceServers = "ollama=http://localhost:11434,vllm=http://localhost:8000,tgi=http://localhost:8080"
+
+// Inference server types accepted by NewInferenceCollector. The openai type covers
+// any other server with an OpenAI-compatible /v1/models endpoint.
+const (
+	InferenceServerOllama = "ollama"
+	InferenceServerVLLM   = "vllm"
+	InferenceServerTGI    = "tgi"
+	InferenceServerOpenAI = "openai"
+)
+
+// InferenceServer is an LLM inference server endpoint probed by the inference collector.
+type InferenceServer struct {
+	Type string
+	URL  string
+}
+
+// InferenceCollector probes local LLM inference servers and reports whether they
+// answer, which models they have loaded, and how long they take to respond.
+type InferenceCollector struct {
+	upDesc       *prometheus.Desc
+	modelsDesc   *prometheus.Desc
+	modelDesc    *prometheus.Desc
+	responseDesc *prometheus.Desc
+
+	servers []InferenceServer
+	client  *http.Client
+}
+
+// NewInferenceCollector creates a new InferenceCollector probing servers, waiting
+// at most timeout for each.
+func NewInferenceCollector(servers []InferenceServer, timeout time.Duration) *InferenceCollector {
+	labels := []string{"server", "url"}
+	for i := range servers {
+		servers[i].URL = strings.TrimSuffix(servers[i].URL, "/")
+	}
+	return &InferenceCollector{
+		upDesc: prometheus.NewDesc(
+			"inference_server_up",
+			"Whether the inference server answered the model list request (1 = up)",
+			labels, nil,
+		),
+		modelsDesc: prometheus.NewDesc(
+			"inference_server_models_loaded",
+			"Number of models loaded by the inference server",
+			labels, nil,
+		),
+		modelDesc: prometheus.NewDesc(
+			"inference_server_model_info",
+			"Model loaded by the inference server, always 1",
+			[]string{"server", "url", "model"}, nil,
+		),
+		responseDesc: prometheus.NewDesc(
+			"inference_server_response_seconds",
+			"Response time of the inference server to the model list request in seconds",
+			labels, nil,
+		),
+		servers: servers,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *InferenceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upDesc
+	ch <- c.modelsDesc
+	ch <- c.modelDesc
+	ch <- c.responseDesc
+}
+
+// Collect queries the loaded models of every server and sends the results to the
+// channel. The model list endpoints are cheap and do not run inference: Ollama
+// /api/ps, TGI /info, and /v1/models of vLLM and other OpenAI-compatible servers.
+// A server that is not running is reported as down without failing the collection,
+// as most hosts run only one of the default servers.
+func (c *InferenceCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.servers {
+		start := time.Now()
+		models, err := c.listModels(s)
+		elapsed := time.Since(start).Seconds()
+		if err != nil {
+			if !errors.Is(err, syscall.ECONNREFUSED) {
+				ch <- prometheus.NewInvalidMetric(c.upDesc, fmt.Errorf("%s: %w", s.URL, err))
+			}
+			ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 0, s.Type, s.URL)
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 1, s.Type, s.URL)
+		ch <- prometheus.MustNewConstMetric(c.responseDesc, prometheus.GaugeValue, elapsed, s.Type, s.URL)
+		ch <- prometheus.MustNewConstMetric(c.modelsDesc, prometheus.GaugeValue, float64(len(models)), s.Type, s.URL)
+		for _, model := range models {
+			ch <- prometheus.MustNewConstMetric(c.modelDesc, prometheus.GaugeValue, 1, s.Type, s.URL, model)
+		}
+	}
+}
+
+// listModels returns the names of the models loaded by the server.
+func (c *InferenceCollector) listModels(s InferenceServer) ([]string, error) {
+	var models []string
+	switch s.Type {
+	case InferenceServerOllama:
+		var ps struct {
+			Models []struct {
+				Name string `json:"name"`
+			} `json:"models"`
+		}
+		if err := c.get(s.URL+"/api/ps", &ps); err != nil {
+			return nil, err
+		}
+		for _, m := range ps.Models {
+			models = append(models, m.Name)
+		}
+	case InferenceServerTGI:
+		// A TGI instance serves exactly one model
+		var info struct {
+			ModelID string `json:"model_id"`
+		}
+		if err := c.get(s.URL+"/info", &info); err != nil {
+			return nil, err
+		}
+		models = append(models, info.ModelID)
+	default:
+		var list struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		if err := c.get(s.URL+"/v1/models", &list); err != nil {
+			return nil, err
+		}
+		for _, m := range list.Data {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+// get fetches url and decodes the JSON response into v.
+func (c *InferenceCollector) get(url string, v any) error {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+	return nil
+}
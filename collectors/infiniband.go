@@ -0,0 +1,175 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// infinibandCounter maps a file in /sys/class/infiniband/<dev>/ports/<port>/{counters,hw_counters}
+// to an exported counter. Data counters are reported by the HCA in units of 4 octets (scale 4).
+type infinibandCounter struct {
+	file  string
+	scale float64
+	desc  *prometheus.Desc
+}
+
+// InfinibandCollector collects InfiniBand/RoCE port state and counters of RDMA
+// devices (e.g. the ConnectX-7 mlx5_* ports).
+type InfinibandCollector struct {
+	counters   []infinibandCounter
+	hwCounters []infinibandCounter
+	upDesc     *prometheus.Desc
+	infoDesc   *prometheus.Desc
+	rateDesc   *prometheus.Desc
+}
+
+// NewInfinibandCollector creates a new InfinibandCollector.
+func NewInfinibandCollector() *InfinibandCollector {
+	labels := []string{"device", "port"}
+	counter := func(file string, scale float64, name, help string) infinibandCounter {
+		return infinibandCounter{file, scale, prometheus.NewDesc(name, help, labels, nil)}
+	}
+
+	return &InfinibandCollector{
+		counters: []infinibandCounter{
+			counter("port_rcv_data", 4, "infiniband_port_receive_bytes_total",
+				"Total bytes received on the port"),
+			counter("port_xmit_data", 4, "infiniband_port_transmit_bytes_total",
+				"Total bytes transmitted on the port"),
+			counter("port_rcv_packets", 1, "infiniband_port_receive_packets_total",
+				"Total packets received on the port"),
+			counter("port_xmit_packets", 1, "infiniband_port_transmit_packets_total",
+				"Total packets transmitted on the port"),
+			counter("port_rcv_errors", 1, "infiniband_port_receive_errors_total",
+				"Total received packets containing an error"),
+			counter("port_xmit_discards", 1, "infiniband_port_transmit_discards_total",
+				"Total outbound packets discarded because the port was down or congested"),
+			counter("port_rcv_remote_physical_errors", 1, "infiniband_port_receive_remote_physical_errors_total",
+				"Total received packets marked with the EBP delimiter"),
+			counter("port_xmit_wait", 1, "infiniband_port_transmit_wait_total",
+				"Total ticks during which the port had data to transmit but could not"),
+			counter("symbol_error", 1, "infiniband_port_symbol_errors_total",
+				"Total minor link errors detected on one or more physical lanes"),
+			counter("link_downed", 1, "infiniband_port_link_downed_total",
+				"Total times the link error recovery process failed and the link went down"),
+			counter("link_error_recovery", 1, "infiniband_port_link_error_recovery_total",
+				"Total times the link error recovery process completed successfully"),
+			counter("local_link_integrity_errors", 1, "infiniband_port_local_link_integrity_errors_total",
+				"Total times the local physical errors exceeded the threshold"),
+			counter("excessive_buffer_overrun_errors", 1, "infiniband_port_excessive_buffer_overrun_errors_total",
+				"Total times consecutive flow control update periods had buffer overruns"),
+		},
+		hwCounters: []infinibandCounter{
+			counter("out_of_buffer", 1, "infiniband_port_out_of_buffer_total",
+				"Total packets dropped because no receive WQE was posted (mlx5)"),
+			counter("out_of_sequence", 1, "infiniband_port_out_of_sequence_total",
+				"Total out-of-sequence packets received (mlx5)"),
+			counter("packet_seq_err", 1, "infiniband_port_packet_sequence_errors_total",
+				"Total received NAKs for packet sequence errors (mlx5)"),
+			counter("local_ack_timeout_err", 1, "infiniband_port_local_ack_timeout_errors_total",
+				"Total ACK timer expirations for sent QP packets (mlx5)"),
+			counter("np_cnp_sent", 1, "infiniband_port_cnp_sent_total",
+				"Total congestion notification packets sent by the notification point (mlx5)"),
+			counter("rp_cnp_handled", 1, "infiniband_port_cnp_handled_total",
+				"Total congestion notification packets handled by the reaction point (mlx5)"),
+			counter("np_ecn_marked_roce_packets", 1, "infiniband_port_ecn_marked_packets_total",
+				"Total ECN-marked RoCE packets received (mlx5)"),
+		},
+		upDesc: prometheus.NewDesc(
+			"infiniband_port_up",
+			"Whether the port is in the ACTIVE state (1 = active, 0 = not active)",
+			labels, nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			"infiniband_port_info",
+			"Port link layer and state (always 1)",
+			append(labels, "link_layer", "state", "physical_state"), nil,
+		),
+		rateDesc: prometheus.NewDesc(
+			"infiniband_port_rate_bytes_per_second",
+			"Current port data rate in bytes per second",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *InfinibandCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, ctr := range c.counters {
+		ch <- ctr.desc
+	}
+	for _, ctr := range c.hwCounters {
+		ch <- ctr.desc
+	}
+	ch <- c.upDesc
+	ch <- c.infoDesc
+	ch <- c.rateDesc
+}
+
+// Collect reads state and counters of every RDMA port and sends them to the channel.
+// If no RDMA devices are present, no metrics are emitted.
+func (c *InfinibandCollector) Collect(ch chan<- prometheus.Metric) {
+	devices, err := os.ReadDir("/sys/class/infiniband")
+	if err != nil {
+		return
+	}
+
+	for _, d := range devices {
+		device := d.Name()
+		portsDir := filepath.Join("/sys/class/infiniband", device, "ports")
+		ports, err := os.ReadDir(portsDir)
+		if err != nil {
+			continue
+		}
+
+		for _, p := range ports {
+			port := p.Name()
+			portDir := filepath.Join(portsDir, port)
+			c.collectPortState(ch, portDir, device, port)
+			collectInfinibandCounters(ch, filepath.Join(portDir, "counters"), c.counters, device, port)
+			collectInfinibandCounters(ch, filepath.Join(portDir, "hw_counters"), c.hwCounters, device, port)
+		}
+	}
+}
+
+// collectPortState reports the logical/physical state and rate of a port.
+func (c *InfinibandCollector) collectPortState(ch chan<- prometheus.Metric, portDir, device, port string) {
+	// state: "4: ACTIVE", phys_state: "5: LinkUp"
+	_, state, _ := strings.Cut(readSysString(filepath.Join(portDir, "state")), ": ")
+	_, physState, _ := strings.Cut(readSysString(filepath.Join(portDir, "phys_state")), ": ")
+	linkLayer := readSysString(filepath.Join(portDir, "link_layer"))
+
+	up := 0.0
+	if state == "ACTIVE" {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, up, device, port)
+	ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, device, port, linkLayer, state, physState)
+
+	// rate: "200 Gb/sec (4X HDR)"
+	if fields := strings.Fields(readSysString(filepath.Join(portDir, "rate"))); len(fields) >= 2 && fields[1] == "Gb/sec" {
+		if gbps, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.rateDesc, prometheus.GaugeValue, gbps*1e9/8, device, port)
+		}
+	}
+}
+
+// collectInfinibandCounters sends the counters present in dir; counters not
+// supported by the device are skipped.
+func collectInfinibandCounters(ch chan<- prometheus.Metric, dir string, counters []infinibandCounter, device, port string) {
+	for _, ctr := range counters {
+		data, err := os.ReadFile(filepath.Join(dir, ctr.file))
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(ctr.desc, prometheus.CounterValue, float64(v)*ctr.scale, device, port)
+	}
+}
@@ -0,0 +1,162 @@
+package collectors
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// journalRestartDelay is the wait before journalctl is started again after it
+// exited, e.g. because journald was restarted.
+const journalRestartDelay = 30 * time.Second
+
+// journalPriorities are the syslog priorities 0-3 counted by the journal
+// collector, indexed by their numeric value.
+var journalPriorities = []string{"emerg", "alert", "crit", "err"}
+
+// journalOtherIdentifier is the identifier label of entries from identifiers not
+// on the allow-list.
+const journalOtherIdentifier = "other"
+
+// journalKey identifies a journal entry counter.
+type journalKey struct {
+	priority   string
+	identifier string
+}
+
+// JournalCollector counts systemd journal entries of priority err and above by
+// following journalctl.
+type JournalCollector struct {
+	entriesDesc *prometheus.Desc
+
+	journalctlPath string
+	identifiers    map[string]bool
+
+	mu     sync.Mutex
+	counts map[journalKey]float64
+}
+
+// NewJournalCollector creates a new JournalCollector and starts following the
+// journal with journalctl. Only entries written after the start are counted. If
+// identifiers is not empty, entries are also counted per syslog identifier; entries
+// of identifiers not in the list are counted as "other", which keeps the number
+// of series bounded.
+func NewJournalCollector(journalctlPath string, identifiers []string) *JournalCollector {
+	labels := []string{"priority"}
+	if len(identifiers) > 0 {
+		labels = append(labels, "identifier")
+	}
+	c := &JournalCollector{
+		entriesDesc: prometheus.NewDesc(
+			"journal_entries_total",
+			"Total number of journal entries of priority err and above written since the exporter started",
+			labels, nil,
+		),
+		journalctlPath: journalctlPath,
+		counts:         make(map[journalKey]float64),
+	}
+	if len(identifiers) > 0 {
+		c.identifiers = make(map[string]bool, len(identifiers))
+		for _, id := range identifiers {
+			c.identifiers[id] = true
+		}
+		identifiers = append(identifiers, journalOtherIdentifier)
+	} else {
+		identifiers = []string{""}
+	}
+	for _, p := range journalPriorities {
+		for _, id := range identifiers {
+			c.counts[journalKey{priority: p, identifier: id}] = 0
+		}
+	}
+
+	go func() {
+		for {
+			if err := c.follow(); err != nil {
+				log.Printf("journal: %v", err)
+			}
+			time.Sleep(journalRestartDelay)
+		}
+	}()
+	return c
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *JournalCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entriesDesc
+}
+
+// Collect sends the entry counters to the channel.
+func (c *JournalCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, n := range c.counts {
+		if c.identifiers != nil {
+			ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.CounterValue, n, k.priority, k.identifier)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.CounterValue, n, k.priority)
+		}
+	}
+}
+
+// follow runs journalctl for new entries of priority err and above in JSON
+// format and counts them until it exits.
+func (c *JournalCollector) follow() error {
+	cmd := exec.Command(c.journalctlPath,
+		"--follow", "--lines=0", "--priority=err", "--output=json",
+		"--output-fields=PRIORITY,SYSLOG_IDENTIFIER,_COMM",
+	)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// Fields that are not valid UTF-8 are arrays of bytes; only strings are used
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		c.count(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	return cmd.Wait()
+}
+
+// count adds a journal entry to its counter.
+func (c *JournalCollector) count(entry map[string]any) {
+	priority, _ := entry["PRIORITY"].(string)
+	if len(priority) != 1 || priority[0] < '0' || int(priority[0]-'0') >= len(journalPriorities) {
+		return
+	}
+	key := journalKey{priority: journalPriorities[priority[0]-'0']}
+	if c.identifiers != nil {
+		id, _ := entry["SYSLOG_IDENTIFIER"].(string)
+		if id == "" {
+			id, _ = entry["_COMM"].(string)
+		}
+		key.identifier = journalOtherIdentifier
+		if c.identifiers[id] {
+			key.identifier = id
+		}
+	}
+
+	c.mu.Lock()
+	c.counts[key]++
+	c.mu.Unlock()
+}
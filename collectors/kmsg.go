@@ -0,0 +1,112 @@
+package collectors
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// kmsgRecordSize is large enough for any single /dev/kmsg record
+// (the kernel limits records to 1024 bytes of text plus metadata).
+const kmsgRecordSize = 8192
+
+// kmsgReopenDelay is how long to wait before reopening /dev/kmsg after a read error.
+const kmsgReopenDelay = 30 * time.Second
+
+// kmsgFollower follows the kernel log in the background; see followKmsg.
+type kmsgFollower struct {
+	mu  sync.Mutex
+	err error // last read error, cleared once /dev/kmsg is reopened
+}
+
+// followKmsg starts a goroutine that reads every record in the kernel log buffer
+// from the beginning via /dev/kmsg and then follows new records, calling handle
+// with the message text of each. Continuation lines (dictionary key=value pairs)
+// are dropped. It returns an error if /dev/kmsg cannot be opened.
+//
+// If reading fails, /dev/kmsg is reopened after kmsgReopenDelay; records that
+// were already handled are skipped. The follower's Err reports the failure
+// until then.
+func followKmsg(handle func(message string)) (*kmsgFollower, error) {
+	f, err := os.Open("/dev/kmsg")
+	if err != nil {
+		return nil, err
+	}
+
+	k := &kmsgFollower{}
+	go k.follow(f, handle)
+	return k, nil
+}
+
+// Err returns the error that interrupted following the kernel log, or nil.
+func (k *kmsgFollower) Err() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.err
+}
+
+// follow reads records from f until a read fails, then reopens /dev/kmsg and
+// continues after the last handled record.
+func (k *kmsgFollower) follow(f *os.File, handle func(message string)) {
+	var lastSeq uint64
+	hasSeq := false
+
+	buf := make([]byte, kmsgRecordSize)
+	for {
+		// Each read returns exactly one record
+		n, err := f.Read(buf)
+		if err != nil {
+			// EPIPE means records were overwritten before we read them; keep going
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+			f.Close()
+			k.setErr(fmt.Errorf("reading /dev/kmsg: %w", err))
+			f = k.reopen()
+			continue
+		}
+
+		// Format: <prio>,<seq>,<timestamp>,<flags>[,...];<message>\n[ KEY=value\n...]
+		record := string(buf[:n])
+		header, message, ok := strings.Cut(record, ";")
+		if !ok {
+			continue
+		}
+		// A reopened /dev/kmsg starts again at the oldest record in the buffer
+		if fields := strings.SplitN(header, ",", 3); len(fields) >= 2 {
+			if seq, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+				if hasSeq && seq <= lastSeq {
+					continue
+				}
+				lastSeq, hasSeq = seq, true
+			}
+		}
+		message, _, _ = strings.Cut(message, "\n")
+		handle(message)
+	}
+}
+
+// reopen opens /dev/kmsg again after kmsgReopenDelay, retrying until it succeeds.
+func (k *kmsgFollower) reopen() *os.File {
+	for {
+		time.Sleep(kmsgReopenDelay)
+		f, err := os.Open("/dev/kmsg")
+		if err == nil {
+			k.setErr(nil)
+			return f
+		}
+		k.setErr(fmt.Errorf("reopening /dev/kmsg: %w", err))
+	}
+}
+
+// setErr records the current follow error.
+func (k *kmsgFollower) setErr(err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.err = err
+}
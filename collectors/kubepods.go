@@ -0,0 +1,239 @@
+package collectors
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultKubeletPodsURL is the kubelet pod list endpoint of the local node.
+const DefaultKubeletPodsURL = "https://127.0.0.1:10250/pods"
+
+// kubePodLogDir holds one <namespace>_<pod>_<uid> directory per pod on the node and
+// is used to name pods when the kubelet API is not reachable.
+const kubePodLogDir = "/var/log/pods"
+
+// kubePodCgroupRe matches a pod-level cgroup and captures its UID: kubepods-
+// <qos>-pod<uid>.slice (with dashes in the UID escaped as underscores) with the
+// systemd cgroup driver, pod<uid> with the cgroupfs driver.
+var kubePodCgroupRe = regexp.MustCompile(`(?:^|/)(?:kubepods(?:-[a-z]+)?-)?pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(?:\.slice)?$`)
+
+// kubePod identifies a pod by namespace and name.
+type kubePod struct {
+	namespace, name string
+}
+
+// KubePodCollector collects CPU, memory, and block I/O usage of Kubernetes pods
+// running on the node from their cgroup v2 accounting files, naming them through
+// the kubelet pod API.
+type KubePodCollector struct {
+	cpuUsageDesc    *prometheus.Desc
+	memoryUsageDesc *prometheus.Desc
+	memoryLimitDesc *prometheus.Desc
+	oomKillsDesc    *prometheus.Desc
+	ioReadDesc      *prometheus.Desc
+	ioWriteDesc     *prometheus.Desc
+
+	podsURL   string
+	tokenFile string
+	client    *http.Client
+}
+
+// NewKubePodCollector creates a new KubePodCollector that lists pods from the kubelet
+// at podsURL, authenticating with the bearer token in tokenFile if set. The kubelet
+// serving certificate is not verified unless verifyTLS is set, as it is usually
+// self-signed.
+func NewKubePodCollector(podsURL, tokenFile string, verifyTLS bool, timeout time.Duration) *KubePodCollector {
+	labels := []string{"namespace", "pod"}
+	ioLabels := []string{"namespace", "pod", "device"}
+	return &KubePodCollector{
+		cpuUsageDesc: prometheus.NewDesc(
+			"kube_pod_cpu_usage_seconds_total",
+			"Total CPU time consumed by all containers of the pod in seconds",
+			labels, nil,
+		),
+		memoryUsageDesc: prometheus.NewDesc(
+			"kube_pod_memory_usage_bytes",
+			"Memory currently charged to the pod in bytes, including page cache",
+			labels, nil,
+		),
+		memoryLimitDesc: prometheus.NewDesc(
+			"kube_pod_memory_limit_bytes",
+			"Memory limit of the pod in bytes (only for pods with a limit)",
+			labels, nil,
+		),
+		oomKillsDesc: prometheus.NewDesc(
+			"kube_pod_oom_kills_total",
+			"Total processes of the pod killed by the OOM killer",
+			labels, nil,
+		),
+		ioReadDesc: prometheus.NewDesc(
+			"kube_pod_io_read_bytes_total",
+			"Total bytes read from the device by the pod",
+			ioLabels, nil,
+		),
+		ioWriteDesc: prometheus.NewDesc(
+			"kube_pod_io_written_bytes_total",
+			"Total bytes written to the device by the pod",
+			ioLabels, nil,
+		),
+		podsURL:   podsURL,
+		tokenFile: tokenFile,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: !verifyTLS},
+			},
+		},
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *KubePodCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuUsageDesc
+	ch <- c.memoryUsageDesc
+	ch <- c.memoryLimitDesc
+	ch <- c.oomKillsDesc
+	ch <- c.ioReadDesc
+	ch <- c.ioWriteDesc
+}
+
+// Collect finds pod cgroups and sends their resource usage to the channel. Pods
+// that neither the kubelet nor /var/log/pods can name are skipped. If the node
+// runs no pods, no metrics are emitted.
+func (c *KubePodCollector) Collect(ch chan<- prometheus.Metric) {
+	cgroups := listKubePodCgroups()
+	if len(cgroups) == 0 {
+		return
+	}
+
+	pods, err := c.fetchPods()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.cpuUsageDesc, fmt.Errorf("kubelet pod list failed, falling back to %s: %w", kubePodLogDir, err))
+		pods = readKubePodLogDirs()
+	}
+
+	devNames := make(map[string]string)
+	for uid, path := range cgroups {
+		pod, ok := pods[uid]
+		if !ok {
+			continue
+		}
+		labels := []string{pod.namespace, pod.name}
+
+		if stats, err := readCgroupKeyedFile(filepath.Join(path, "cpu.stat")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.cpuUsageDesc, prometheus.CounterValue, stats["usage_usec"]/1e6, labels...)
+		}
+		if v, ok := readProcSysFloat(filepath.Join(path, "memory.current")); ok {
+			ch <- prometheus.MustNewConstMetric(c.memoryUsageDesc, prometheus.GaugeValue, v, labels...)
+		}
+		// memory.max holds "max" when unlimited, which does not parse
+		if v, ok := readProcSysFloat(filepath.Join(path, "memory.max")); ok {
+			ch <- prometheus.MustNewConstMetric(c.memoryLimitDesc, prometheus.GaugeValue, v, labels...)
+		}
+		if events, err := readCgroupKeyedFile(filepath.Join(path, "memory.events")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.oomKillsDesc, prometheus.CounterValue, events["oom_kill"], labels...)
+		}
+
+		if stats, err := readCgroupIOStat(filepath.Join(path, "io.stat")); err == nil {
+			for majMin, values := range stats {
+				device, ok := devNames[majMin]
+				if !ok {
+					device = blockDeviceName(majMin)
+					devNames[majMin] = device
+				}
+				ioLabels := append(labels[:len(labels):len(labels)], device)
+				ch <- prometheus.MustNewConstMetric(c.ioReadDesc, prometheus.CounterValue, values["rbytes"], ioLabels...)
+				ch <- prometheus.MustNewConstMetric(c.ioWriteDesc, prometheus.CounterValue, values["wbytes"], ioLabels...)
+			}
+		}
+	}
+}
+
+// fetchPods lists the pods of the node from the kubelet, keyed by UID.
+func (c *KubePodCollector) fetchPods() (map[string]kubePod, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, c.podsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+				UID       string `json:"uid"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	pods := make(map[string]kubePod, len(list.Items))
+	for _, item := range list.Items {
+		pods[item.Metadata.UID] = kubePod{namespace: item.Metadata.Namespace, name: item.Metadata.Name}
+	}
+	return pods, nil
+}
+
+// readKubePodLogDirs names pods from the <namespace>_<pod>_<uid> directories of
+// /var/log/pods, keyed by UID. Namespaces and pod names cannot contain underscores.
+func readKubePodLogDirs() map[string]kubePod {
+	pods := make(map[string]kubePod)
+	entries, err := os.ReadDir(kubePodLogDir)
+	if err != nil {
+		return pods
+	}
+	for _, e := range entries {
+		parts := strings.Split(e.Name(), "_")
+		if len(parts) != 3 {
+			continue
+		}
+		pods[parts[2]] = kubePod{namespace: parts[0], name: parts[1]}
+	}
+	return pods
+}
+
+// listKubePodCgroups walks the cgroup hierarchy for pod-level cgroups and returns
+// their absolute paths keyed by pod UID.
+func listKubePodCgroups() map[string]string {
+	cgroups := make(map[string]string)
+	_ = filepath.WalkDir(cgroupRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		m := kubePodCgroupRe.FindStringSubmatch(path)
+		if m == nil {
+			return nil
+		}
+		cgroups[strings.ReplaceAll(m[1], "_", "-")] = path
+		return filepath.SkipDir
+	})
+	return cgroups
+}
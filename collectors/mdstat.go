@@ -0,0 +1,191 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// mdDiskCountRe matches the "[n/m]" required/in-sync disk counts of an array status line.
+	mdDiskCountRe = regexp.MustCompile(`\[(\d+)/(\d+)\]`)
+	// mdBlocksRe matches the block count at the start of an array status line.
+	mdBlocksRe = regexp.MustCompile(`^(\d+) blocks`)
+	// mdSyncRe matches a sync progress line, e.g. "resync = 12.6% (246123456/1953382464)".
+	mdSyncRe = regexp.MustCompile(`(resync|recovery|reshape|check)\s*=\s*([\d.]+)%`)
+)
+
+// mdArray holds the parsed state of a single md array from /proc/mdstat.
+type mdArray struct {
+	name          string
+	state         string
+	level         string
+	disksActive   float64
+	disksFailed   float64
+	disksSpare    float64
+	disksRequired float64
+	blocks        float64
+	syncAction    string
+	syncProgress  float64
+}
+
+// MDStatCollector collects Linux software RAID status from /proc/mdstat.
+type MDStatCollector struct {
+	stateDesc         *prometheus.Desc
+	disksDesc         *prometheus.Desc
+	disksRequiredDesc *prometheus.Desc
+	blocksDesc        *prometheus.Desc
+	syncProgressDesc  *prometheus.Desc
+}
+
+// NewMDStatCollector creates a new MDStatCollector.
+func NewMDStatCollector() *MDStatCollector {
+	return &MDStatCollector{
+		stateDesc: prometheus.NewDesc(
+			"md_state",
+			"State of the md array (1 for the current state)",
+			[]string{"device", "level", "state"}, nil,
+		),
+		disksDesc: prometheus.NewDesc(
+			"md_disks",
+			"Number of member disks of the md array by state (active, failed, spare)",
+			[]string{"device", "state"}, nil,
+		),
+		disksRequiredDesc: prometheus.NewDesc(
+			"md_disks_required",
+			"Number of disks the md array requires to be fully redundant",
+			[]string{"device"}, nil,
+		),
+		blocksDesc: prometheus.NewDesc(
+			"md_blocks",
+			"Size of the md array in 1 KiB blocks",
+			[]string{"device"}, nil,
+		),
+		syncProgressDesc: prometheus.NewDesc(
+			"md_sync_progress_percent",
+			"Progress of a running resync, recovery, reshape, or check in percent",
+			[]string{"device", "action"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *MDStatCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.stateDesc
+	ch <- c.disksDesc
+	ch <- c.disksRequiredDesc
+	ch <- c.blocksDesc
+	ch <- c.syncProgressDesc
+}
+
+// Collect parses /proc/mdstat and sends per-array metrics to the channel.
+// If the md driver is not loaded, no metrics are emitted.
+func (c *MDStatCollector) Collect(ch chan<- prometheus.Metric) {
+	arrays, err := readMDStat()
+	if err != nil {
+		return
+	}
+
+	for _, a := range arrays {
+		ch <- prometheus.MustNewConstMetric(c.stateDesc, prometheus.GaugeValue, 1, a.name, a.level, a.state)
+		ch <- prometheus.MustNewConstMetric(c.disksDesc, prometheus.GaugeValue, a.disksActive, a.name, "active")
+		ch <- prometheus.MustNewConstMetric(c.disksDesc, prometheus.GaugeValue, a.disksFailed, a.name, "failed")
+		ch <- prometheus.MustNewConstMetric(c.disksDesc, prometheus.GaugeValue, a.disksSpare, a.name, "spare")
+		ch <- prometheus.MustNewConstMetric(c.disksRequiredDesc, prometheus.GaugeValue, a.disksRequired, a.name)
+		ch <- prometheus.MustNewConstMetric(c.blocksDesc, prometheus.GaugeValue, a.blocks, a.name)
+
+		if a.syncAction != "" {
+			ch <- prometheus.MustNewConstMetric(c.syncProgressDesc, prometheus.GaugeValue, a.syncProgress, a.name, a.syncAction)
+		}
+	}
+}
+
+// readMDStat parses /proc/mdstat into a list of arrays.
+func readMDStat() ([]*mdArray, error) {
+	f, err := os.Open("/proc/mdstat")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var arrays []*mdArray
+	var current *mdArray
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		// Array header: md0 : active raid1 sdb1[1] sda1[0](F)
+		if strings.HasPrefix(line, "md") && strings.Contains(line, " : ") {
+			current = parseMDHeader(line)
+			arrays = append(arrays, current)
+			continue
+		}
+		if current == nil || trimmed == "" {
+			continue
+		}
+
+		if m := mdBlocksRe.FindStringSubmatch(trimmed); m != nil {
+			current.blocks, _ = strconv.ParseFloat(m[1], 64)
+		}
+		if m := mdDiskCountRe.FindStringSubmatch(trimmed); m != nil {
+			current.disksRequired, _ = strconv.ParseFloat(m[1], 64)
+			current.disksActive, _ = strconv.ParseFloat(m[2], 64)
+		}
+		if m := mdSyncRe.FindStringSubmatch(trimmed); m != nil {
+			current.syncAction = m[1]
+			current.syncProgress, _ = strconv.ParseFloat(m[2], 64)
+		}
+	}
+
+	return arrays, scanner.Err()
+}
+
+// parseMDHeader parses the first line of an array entry in /proc/mdstat.
+func parseMDHeader(line string) *mdArray {
+	name, rest, _ := strings.Cut(line, " : ")
+	fields := strings.Fields(rest)
+
+	a := &mdArray{name: strings.TrimSpace(name)}
+	if len(fields) == 0 {
+		return a
+	}
+
+	a.state = fields[0]
+	fields = fields[1:]
+
+	// Optional "(auto-read-only)" / "(read-only)" qualifier
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "(") {
+		a.state = a.state + " " + fields[0]
+		fields = fields[1:]
+	}
+
+	// Inactive arrays have no personality; members follow the state directly
+	if len(fields) > 0 && !strings.Contains(fields[0], "[") {
+		a.level = fields[0]
+		fields = fields[1:]
+	}
+
+	var members float64
+	for _, member := range fields {
+		switch {
+		case strings.HasSuffix(member, "(F)"):
+			a.disksFailed++
+		case strings.HasSuffix(member, "(S)"):
+			a.disksSpare++
+		default:
+			members++
+		}
+	}
+
+	// Arrays without redundancy (raid0, linear) have no "[n/m]" status;
+	// default to the number of healthy members listed in the header.
+	a.disksActive = members
+	a.disksRequired = members
+	return a
+}
@@ -9,10 +9,46 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-// MemoryCollector collects RAM total and used metrics from /proc/meminfo.
+// vmstatCounter maps one or more /proc/vmstat keys to an exported counter metric.
+// When several keys are given (e.g. per-zone allocstall_* counters), the values
+// of all keys present in the running kernel are summed.
+type vmstatCounter struct {
+	keys []string
+	desc *prometheus.Desc
+}
+
+// newVMStatCounter creates a vmstatCounter for the given /proc/vmstat keys.
+func newVMStatCounter(name, help string, keys ...string) vmstatCounter {
+	return vmstatCounter{
+		keys: keys,
+		desc: prometheus.NewDesc(name, help, nil, nil),
+	}
+}
+
+// meminfoGauge maps a /proc/meminfo key (in kB) to an exported gauge in bytes.
+type meminfoGauge struct {
+	key  string
+	desc *prometheus.Desc
+}
+
+// newMeminfoGauge creates a meminfoGauge for the given /proc/meminfo key.
+func newMeminfoGauge(key, name, help string) meminfoGauge {
+	return meminfoGauge{
+		key:  key,
+		desc: prometheus.NewDesc(name, help, nil, nil),
+	}
+}
+
+// MemoryCollector collects RAM, kernel memory, transparent hugepage, and reclaim metrics from
+// /proc/meminfo and /proc/vmstat.
 type MemoryCollector struct {
-	totalDesc *prometheus.Desc
-	usedDesc  *prometheus.Desc
+	totalDesc            *prometheus.Desc
+	usedDesc             *prometheus.Desc
+	overcommitPolicyDesc *prometheus.Desc
+	overcommitRatioDesc  *prometheus.Desc
+
+	meminfoGauges  []meminfoGauge
+	vmstatCounters []vmstatCounter
 }
 
 // NewMemoryCollector creates a new MemoryCollector.
@@ -28,6 +64,67 @@ func NewMemoryCollector() *MemoryCollector {
 			"Used RAM in bytes (total - free - buffers - cached)",
 			nil, nil,
 		),
+		overcommitPolicyDesc: prometheus.NewDesc(
+			"memory_overcommit_policy",
+			"Kernel overcommit policy from vm.overcommit_memory (0 = heuristic, 1 = always, 2 = never)",
+			nil, nil,
+		),
+		overcommitRatioDesc: prometheus.NewDesc(
+			"memory_overcommit_ratio_percent",
+			"Percentage of RAM counted towards CommitLimit when overcommit policy is 2 (vm.overcommit_ratio)",
+			nil, nil,
+		),
+		meminfoGauges: []meminfoGauge{
+			newMeminfoGauge("AnonHugePages", "memory_anon_hugepages_bytes",
+				"Anonymous memory backed by transparent hugepages in bytes"),
+			newMeminfoGauge("VmallocUsed", "memory_vmalloc_used_bytes",
+				"Kernel vmalloc area in use in bytes"),
+			newMeminfoGauge("PageTables", "memory_page_tables_bytes",
+				"Memory used by page tables in bytes"),
+			newMeminfoGauge("KernelStack", "memory_kernel_stack_bytes",
+				"Memory used by kernel stacks in bytes"),
+			newMeminfoGauge("Percpu", "memory_percpu_bytes",
+				"Memory allocated to the per-CPU allocator in bytes"),
+			newMeminfoGauge("SReclaimable", "memory_slab_reclaimable_bytes",
+				"Reclaimable kernel slab memory in bytes"),
+			newMeminfoGauge("SUnreclaim", "memory_slab_unreclaimable_bytes",
+				"Unreclaimable kernel slab memory in bytes"),
+			newMeminfoGauge("Committed_AS", "memory_committed_as_bytes",
+				"Memory committed to all processes (allocated address space) in bytes"),
+			newMeminfoGauge("CommitLimit", "memory_commit_limit_bytes",
+				"Total memory that can be committed before allocations fail under overcommit policy 2, in bytes"),
+		},
+		vmstatCounters: []vmstatCounter{
+			newVMStatCounter("memory_thp_fault_alloc_total",
+				"Total number of transparent hugepages allocated on page fault", "thp_fault_alloc"),
+			newVMStatCounter("memory_thp_fault_fallback_total",
+				"Total number of page faults that fell back to regular pages because a hugepage could not be allocated", "thp_fault_fallback"),
+			newVMStatCounter("memory_thp_collapse_alloc_total",
+				"Total number of transparent hugepages allocated by khugepaged to collapse regular pages", "thp_collapse_alloc"),
+			newVMStatCounter("memory_thp_collapse_alloc_failed_total",
+				"Total number of failed khugepaged hugepage allocations", "thp_collapse_alloc_failed"),
+			newVMStatCounter("memory_thp_split_page_total",
+				"Total number of transparent hugepages split into regular pages", "thp_split_page"),
+			newVMStatCounter("memory_thp_split_pmd_total",
+				"Total number of transparent hugepage PMD mappings split into PTE mappings", "thp_split_pmd"),
+			newVMStatCounter("memory_compact_stall_total",
+				"Total number of allocations stalled for direct memory compaction", "compact_stall"),
+			newVMStatCounter("memory_compact_fail_total",
+				"Total number of direct compactions that failed to free a suitable page", "compact_fail"),
+			newVMStatCounter("memory_compact_success_total",
+				"Total number of direct compactions that freed a suitable page", "compact_success"),
+			newVMStatCounter("memory_allocstall_total",
+				"Total number of allocations stalled for direct reclaim",
+				"allocstall", "allocstall_dma", "allocstall_dma32", "allocstall_normal", "allocstall_movable", "allocstall_device"),
+			newVMStatCounter("memory_pgscan_direct_total",
+				"Total number of pages scanned by direct reclaim", "pgscan_direct"),
+			newVMStatCounter("memory_pgsteal_direct_total",
+				"Total number of pages reclaimed by direct reclaim", "pgsteal_direct"),
+			newVMStatCounter("memory_pgscan_kswapd_total",
+				"Total number of pages scanned by kswapd background reclaim", "pgscan_kswapd"),
+			newVMStatCounter("memory_pgsteal_kswapd_total",
+				"Total number of pages reclaimed by kswapd background reclaim", "pgsteal_kswapd"),
+		},
 	}
 }
 
@@ -35,10 +132,26 @@ func NewMemoryCollector() *MemoryCollector {
 func (c *MemoryCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.totalDesc
 	ch <- c.usedDesc
+	ch <- c.overcommitPolicyDesc
+	ch <- c.overcommitRatioDesc
+	for _, g := range c.meminfoGauges {
+		ch <- g.desc
+	}
+	for _, v := range c.vmstatCounters {
+		ch <- v.desc
+	}
 }
 
-// Collect reads /proc/meminfo and sends memory metrics to the channel.
+// Collect reads /proc/meminfo and /proc/vmstat and sends memory metrics to the channel.
 func (c *MemoryCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectMemInfo(ch)
+	c.collectOvercommit(ch)
+	c.collectVMStat(ch)
+}
+
+// collectMemInfo reports RAM, hugepage, and kernel memory usage from /proc/meminfo.
+// Fields missing from the running kernel are skipped.
+func (c *MemoryCollector) collectMemInfo(ch chan<- prometheus.Metric) {
 	memInfo, err := readMemInfo()
 	if err != nil {
 		return
@@ -59,6 +172,48 @@ func (c *MemoryCollector) Collect(ch chan<- prometheus.Metric) {
 
 	ch <- prometheus.MustNewConstMetric(c.totalDesc, prometheus.GaugeValue, totalBytes)
 	ch <- prometheus.MustNewConstMetric(c.usedDesc, prometheus.GaugeValue, usedBytes)
+
+	for _, g := range c.meminfoGauges {
+		valKB, ok := memInfo[g.key]
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, float64(valKB)*1024)
+	}
+}
+
+// collectOvercommit reports the kernel overcommit settings from /proc/sys/vm.
+func (c *MemoryCollector) collectOvercommit(ch chan<- prometheus.Metric) {
+	if policy, ok := readProcSysFloat("/proc/sys/vm/overcommit_memory"); ok {
+		ch <- prometheus.MustNewConstMetric(c.overcommitPolicyDesc, prometheus.GaugeValue, policy)
+	}
+	if ratio, ok := readProcSysFloat("/proc/sys/vm/overcommit_ratio"); ok {
+		ch <- prometheus.MustNewConstMetric(c.overcommitRatioDesc, prometheus.GaugeValue, ratio)
+	}
+}
+
+// collectVMStat reports the configured /proc/vmstat event counters.
+// Counters missing from the running kernel are skipped.
+func (c *MemoryCollector) collectVMStat(ch chan<- prometheus.Metric) {
+	vmstat, err := readVMStat()
+	if err != nil {
+		return
+	}
+
+	for _, v := range c.vmstatCounters {
+		var total uint64
+		found := false
+		for _, key := range v.keys {
+			if val, ok := vmstat[key]; ok {
+				total += val
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(v.desc, prometheus.CounterValue, float64(total))
+	}
 }
 
 // readMemInfo parses /proc/meminfo into a map of key -> value in kB.
@@ -92,3 +247,43 @@ func readMemInfo() (map[string]uint64, error) {
 
 	return info, scanner.Err()
 }
+
+// readVMStat parses /proc/vmstat into a map of key -> value.
+func readVMStat() (map[string]uint64, error) {
+	f, err := os.Open("/proc/vmstat")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		stats[fields[0]] = val
+	}
+
+	return stats, scanner.Err()
+}
+
+// readProcSysFloat reads a file containing a single numeric value, such as a
+// /proc/sys tunable.
+func readProcSysFloat(path string) (float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,119 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// neighborTables maps address families to their neighbor table statistics and sysctls.
+var neighborTables = []struct {
+	family    string
+	statFile  string
+	sysctlDir string
+}{
+	{"ipv4", "/proc/net/stat/arp_cache", "/proc/sys/net/ipv4/neigh/default"},
+	{"ipv6", "/proc/net/stat/ndisc_cache", "/proc/sys/net/ipv6/neigh/default"},
+}
+
+// NeighborCollector collects neighbor (ARP/NDP) table usage and garbage collection thresholds.
+type NeighborCollector struct {
+	entriesDesc    *prometheus.Desc
+	gcThreshDesc   *prometheus.Desc
+	overflowsDesc  *prometheus.Desc
+	arpEntriesDesc *prometheus.Desc
+}
+
+// NewNeighborCollector creates a new NeighborCollector.
+func NewNeighborCollector() *NeighborCollector {
+	return &NeighborCollector{
+		entriesDesc: prometheus.NewDesc(
+			"neighbor_entries",
+			"Number of entries in the neighbor table",
+			[]string{"family"}, nil,
+		),
+		gcThreshDesc: prometheus.NewDesc(
+			"neighbor_gc_threshold_entries",
+			"Neighbor table garbage collection threshold (gc_thresh1..3); gc_thresh3 is the hard limit",
+			[]string{"family", "level"}, nil,
+		),
+		overflowsDesc: prometheus.NewDesc(
+			"neighbor_table_overflows_total",
+			"Total times a neighbor entry could not be allocated because the table was full",
+			[]string{"family"}, nil,
+		),
+		arpEntriesDesc: prometheus.NewDesc(
+			"arp_entries",
+			"Number of IPv4 ARP entries per network interface",
+			[]string{"interface"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *NeighborCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entriesDesc
+	ch <- c.gcThreshDesc
+	ch <- c.overflowsDesc
+	ch <- c.arpEntriesDesc
+}
+
+// Collect reads neighbor table statistics and sends them to the channel.
+func (c *NeighborCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, t := range neighborTables {
+		// "entries" is a global count repeated on every CPU line
+		if rows, err := readProcNetStat(t.statFile); err == nil && len(rows) > 0 {
+			if v, ok := rows[0]["entries"]; ok {
+				ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.GaugeValue, v, t.family)
+			}
+			if v, ok := sumProcNetStat(rows, "table_fulls"); ok {
+				ch <- prometheus.MustNewConstMetric(c.overflowsDesc, prometheus.CounterValue, v, t.family)
+			}
+		}
+
+		for _, level := range []string{"1", "2", "3"} {
+			v, err := strconv.ParseFloat(readSysString(filepath.Join(t.sysctlDir, "gc_thresh"+level)), 64)
+			if err != nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.gcThreshDesc, prometheus.GaugeValue, v, t.family, level)
+		}
+	}
+
+	counts := readARPEntries("/proc/net/arp")
+	ifaces := make([]string, 0, len(counts))
+	for iface := range counts {
+		ifaces = append(ifaces, iface)
+	}
+	sort.Strings(ifaces)
+	for _, iface := range ifaces {
+		ch <- prometheus.MustNewConstMetric(c.arpEntriesDesc, prometheus.GaugeValue, counts[iface], iface)
+	}
+}
+
+// readARPEntries counts the entries of /proc/net/arp per device.
+func readARPEntries(path string) map[string]float64 {
+	counts := make(map[string]float64)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return counts
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header: IP address  HW type  Flags  HW address  Mask  Device
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		counts[fields[5]]++
+	}
+	return counts
+}
@@ -0,0 +1,333 @@
+package collectors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"syscall"
+)
+
+// Link attributes from <linux/if_link.h> not defined by package syscall.
+const (
+	iflaStats64        = 23
+	iflaCarrierChanges = 35
+	iflaPropList       = 52
+	iflaAltIfname      = 53
+	nlaTypeMask        = 0x3fff // strips NLA_F_NESTED and NLA_F_NET_BYTEORDER
+	nlaFNested         = 0x8000
+	rtaAlignTo         = 4
+	rtaHeaderBytes     = 4
+)
+
+// Field indexes of struct rtnl_link_stats64 (IFLA_STATS64), all uint64.
+const (
+	linkStatRxPackets     = 0
+	linkStatTxPackets     = 1
+	linkStatRxBytes       = 2
+	linkStatTxBytes       = 3
+	linkStatRxErrors      = 4
+	linkStatTxErrors      = 5
+	linkStatRxDropped     = 6
+	linkStatTxDropped     = 7
+	linkStatMulticast     = 8
+	linkStatCollisions    = 9
+	linkStatRxFrameErrors = 13
+	linkStatRxFifoErrors  = 14
+	linkStatTxFifoErrors  = 18
+)
+
+// linkOperStates maps IFLA_OPERSTATE values (RFC 2863) to the names used in
+// /sys/class/net/<iface>/operstate.
+var linkOperStates = []string{"unknown", "notpresent", "down", "lowerlayerdown", "testing", "dormant", "up"}
+
+// netlinkLink is the state and statistics of one interface from an RTM_GETLINK dump.
+type netlinkLink struct {
+	index          int
+	name           string
+	altName        string // first alternative name, if any
+	mac            string
+	operstate      string
+	carrierChanges uint64
+	stats          []uint64 // rtnl_link_stats64 fields
+}
+
+// readLinks dumps all interfaces of the calling thread's network namespace.
+func readLinks() ([]netlinkLink, error) {
+	rib, err := syscall.NetlinkRIB(syscall.RTM_GETLINK, syscall.AF_UNSPEC)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := syscall.ParseNetlinkMessage(rib)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []netlinkLink
+	for i := range msgs {
+		if msgs[i].Header.Type != syscall.RTM_NEWLINK {
+			continue
+		}
+		attrs, err := syscall.ParseNetlinkRouteAttr(&msgs[i])
+		if err != nil {
+			continue
+		}
+
+		var link netlinkLink
+		if len(msgs[i].Data) >= syscall.SizeofIfInfomsg {
+			link.index = int(int32(binary.NativeEndian.Uint32(msgs[i].Data[4:8])))
+		}
+		for _, a := range attrs {
+			switch a.Attr.Type & nlaTypeMask {
+			case syscall.IFLA_IFNAME:
+				link.name = nullTerminated(a.Value)
+			case syscall.IFLA_ADDRESS:
+				link.mac = net.HardwareAddr(a.Value).String()
+			case syscall.IFLA_OPERSTATE:
+				if len(a.Value) >= 1 && int(a.Value[0]) < len(linkOperStates) {
+					link.operstate = linkOperStates[a.Value[0]]
+				}
+			case iflaCarrierChanges:
+				if len(a.Value) >= 4 {
+					link.carrierChanges = uint64(binary.NativeEndian.Uint32(a.Value))
+				}
+			case iflaStats64:
+				for b := a.Value; len(b) >= 8; b = b[8:] {
+					link.stats = append(link.stats, binary.NativeEndian.Uint64(b))
+				}
+			case iflaPropList:
+				for _, nested := range parseNestedAttrs(a.Value) {
+					if nested.typ == iflaAltIfname && link.altName == "" {
+						link.altName = nullTerminated(nested.value)
+					}
+				}
+			}
+		}
+		if link.name != "" {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// readInterfaceAltNames returns the first alternative name (ip link property
+// altname, e.g. set by a systemd .link file's AlternativeName=) of every interface
+// that has one, keyed by kernel interface name.
+func readInterfaceAltNames() (map[string]string, error) {
+	links, err := readLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	altNames := make(map[string]string)
+	for _, link := range links {
+		if link.altName != "" {
+			altNames[link.name] = link.altName
+		}
+	}
+	return altNames, nil
+}
+
+// nestedAttr is one attribute inside a nested netlink attribute.
+type nestedAttr struct {
+	typ   uint16
+	value []byte
+}
+
+// parseNestedAttrs splits the payload of a nested netlink attribute into its attributes.
+func parseNestedAttrs(b []byte) []nestedAttr {
+	var attrs []nestedAttr
+	for len(b) >= rtaHeaderBytes {
+		length := int(binary.NativeEndian.Uint16(b[0:2]))
+		if length < rtaHeaderBytes || length > len(b) {
+			break
+		}
+		attrs = append(attrs, nestedAttr{
+			typ:   binary.NativeEndian.Uint16(b[2:4]) & nlaTypeMask,
+			value: b[rtaHeaderBytes:length],
+		})
+
+		aligned := (length + rtaAlignTo - 1) &^ (rtaAlignTo - 1)
+		if aligned > len(b) {
+			break
+		}
+		b = b[aligned:]
+	}
+	return attrs
+}
+
+// nullTerminated returns b up to the first NUL byte as a string.
+func nullTerminated(b []byte) string {
+	if end := bytes.IndexByte(b, 0); end >= 0 {
+		b = b[:end]
+	}
+	return string(b)
+}
+
+// Generic netlink constants from <linux/genetlink.h>.
+const (
+	genlIDCtrl             = 0x10
+	genlCtrlCmdGetFamily   = 3
+	genlCtrlAttrFamilyID   = 1
+	genlCtrlAttrFamilyName = 2
+	genlHeaderBytes        = 4
+	netlinkRecvBufferBytes = 65536
+	netlinkRecvTimeoutSecs = 2
+)
+
+// genlConn is a generic netlink socket bound to one protocol family (e.g. "ethtool").
+type genlConn struct {
+	fd     int
+	family uint16
+	seq    uint32
+}
+
+// dialGenl opens a generic netlink socket and resolves the ID of the named family.
+func dialGenl(familyName string) (*genlConn, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_GENERIC)
+	if err != nil {
+		return nil, err
+	}
+	c := &genlConn{fd: fd}
+
+	tv := syscall.Timeval{Sec: netlinkRecvTimeoutSecs}
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	attrs, err := c.request(genlIDCtrl, genlCtrlCmdGetFamily, 1,
+		netlinkAttr(genlCtrlAttrFamilyName, append([]byte(familyName), 0)))
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	for _, a := range attrs {
+		if a.typ == genlCtrlAttrFamilyID && len(a.value) >= 2 {
+			c.family = binary.NativeEndian.Uint16(a.value)
+		}
+	}
+	if c.family == 0 {
+		c.Close()
+		return nil, syscall.ENOENT
+	}
+	return c, nil
+}
+
+// Close closes the underlying socket.
+func (c *genlConn) Close() error {
+	return syscall.Close(c.fd)
+}
+
+// request sends a generic netlink command with the given attributes and returns the
+// attributes of the reply.
+func (c *genlConn) request(msgType uint16, cmd, version uint8, attrs ...[]byte) ([]nestedAttr, error) {
+	if err := c.send(msgType, syscall.NLM_F_REQUEST, cmd, version, attrs); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, netlinkRecvBufferBytes)
+	for {
+		n, _, err := syscall.Recvfrom(c.fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != c.seq {
+				continue
+			}
+			if m.Header.Type == syscall.NLMSG_ERROR {
+				if len(m.Data) >= 4 {
+					if errno := int32(binary.NativeEndian.Uint32(m.Data[0:4])); errno < 0 {
+						return nil, syscall.Errno(-errno)
+					}
+				}
+				return nil, nil
+			}
+			if len(m.Data) < genlHeaderBytes {
+				return nil, syscall.EINVAL
+			}
+			return parseNestedAttrs(m.Data[genlHeaderBytes:]), nil
+		}
+	}
+}
+
+// dump sends a generic netlink dump command with the given attributes and returns
+// the attributes of every reply message.
+func (c *genlConn) dump(msgType uint16, cmd, version uint8, attrs ...[]byte) ([][]nestedAttr, error) {
+	if err := c.send(msgType, syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP, cmd, version, attrs); err != nil {
+		return nil, err
+	}
+
+	var replies [][]nestedAttr
+	buf := make([]byte, netlinkRecvBufferBytes)
+	for {
+		n, _, err := syscall.Recvfrom(c.fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != c.seq {
+				continue
+			}
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return replies, nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) >= 4 {
+					if errno := int32(binary.NativeEndian.Uint32(m.Data[0:4])); errno < 0 {
+						return nil, syscall.Errno(-errno)
+					}
+				}
+				return replies, nil
+			}
+			if len(m.Data) < genlHeaderBytes {
+				return nil, syscall.EINVAL
+			}
+			// The receive buffer is reused, so the attributes must be copied
+			data := append([]byte(nil), m.Data[genlHeaderBytes:]...)
+			replies = append(replies, parseNestedAttrs(data))
+		}
+	}
+}
+
+// send sends a generic netlink message with a new sequence number.
+func (c *genlConn) send(msgType, flags uint16, cmd, version uint8, attrs [][]byte) error {
+	c.seq++
+
+	var payload []byte
+	for _, a := range attrs {
+		payload = append(payload, a...)
+	}
+	msg := make([]byte, syscall.NLMSG_HDRLEN+genlHeaderBytes, syscall.NLMSG_HDRLEN+genlHeaderBytes+len(payload))
+	binary.NativeEndian.PutUint32(msg[0:], uint32(cap(msg)))
+	binary.NativeEndian.PutUint16(msg[4:], msgType)
+	binary.NativeEndian.PutUint16(msg[6:], flags)
+	binary.NativeEndian.PutUint32(msg[8:], c.seq)
+	msg[syscall.NLMSG_HDRLEN] = cmd
+	msg[syscall.NLMSG_HDRLEN+1] = version
+	msg = append(msg, payload...)
+
+	return syscall.Sendto(c.fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK})
+}
+
+// netlinkAttr encodes a netlink attribute, padded to the attribute alignment.
+func netlinkAttr(typ uint16, value []byte) []byte {
+	length := rtaHeaderBytes + len(value)
+	b := make([]byte, (length+rtaAlignTo-1)&^(rtaAlignTo-1))
+	binary.NativeEndian.PutUint16(b[0:], uint16(length))
+	binary.NativeEndian.PutUint16(b[2:], typ)
+	copy(b[rtaHeaderBytes:], value)
+	return b
+}
@@ -0,0 +1,65 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// NamedNetnsDir is where "ip netns add" (and tools following its convention) bind
+// mounts named network namespaces.
+const NamedNetnsDir = "/var/run/netns"
+
+// listNamedNetns returns the names of the network namespaces in NamedNetnsDir.
+func listNamedNetns() []string {
+	entries, err := os.ReadDir(NamedNetnsDir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// readNetnsLinks dumps the interfaces of the named network namespace.
+//
+// Netlink sockets belong to the namespace of the thread that creates them, so the
+// dump runs on a dedicated OS thread that joins the namespace. The thread is left
+// locked when the goroutine exits, which makes the runtime discard it instead of
+// reusing it in the wrong namespace. Sysfs cannot be used: /sys/class/net always
+// shows the namespace sysfs was mounted in.
+func readNetnsLinks(name string) ([]netlinkLink, error) {
+	type result struct {
+		links []netlinkLink
+		err   error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		runtime.LockOSThread()
+
+		f, err := os.Open(filepath.Join(NamedNetnsDir, name))
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer f.Close()
+
+		if err := unix.Setns(int(f.Fd()), unix.CLONE_NEWNET); err != nil {
+			done <- result{err: err}
+			return
+		}
+		links, err := readLinks()
+		done <- result{links, err}
+	}()
+
+	r := <-done
+	return r.links, r.err
+}
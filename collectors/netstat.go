@@ -0,0 +1,169 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// netstatField maps a protocol/field pair from /proc/net/snmp or /proc/net/netstat
+// to an exported metric.
+type netstatField struct {
+	protocol  string
+	field     string
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+}
+
+// NetstatCollector collects IP, TCP, and UDP protocol statistics from
+// /proc/net/snmp and /proc/net/netstat.
+type NetstatCollector struct {
+	fields []netstatField
+}
+
+// NewNetstatCollector creates a new NetstatCollector.
+func NewNetstatCollector() *NetstatCollector {
+	counter := func(protocol, field, name, help string) netstatField {
+		return netstatField{protocol, field, prometheus.NewDesc(name, help, nil, nil), prometheus.CounterValue}
+	}
+	gauge := func(protocol, field, name, help string) netstatField {
+		return netstatField{protocol, field, prometheus.NewDesc(name, help, nil, nil), prometheus.GaugeValue}
+	}
+
+	return &NetstatCollector{
+		fields: []netstatField{
+			// /proc/net/snmp
+			counter("Ip", "InReceives", "netstat_ip_in_receives_total",
+				"Total IP datagrams received, including those with errors"),
+			counter("Ip", "InDiscards", "netstat_ip_in_discards_total",
+				"Total received IP datagrams discarded for lack of resources"),
+			counter("Ip", "OutDiscards", "netstat_ip_out_discards_total",
+				"Total outgoing IP datagrams discarded for lack of resources"),
+			counter("Ip", "ReasmFails", "netstat_ip_reassembly_failures_total",
+				"Total IP reassembly failures"),
+			counter("Tcp", "ActiveOpens", "netstat_tcp_active_opens_total",
+				"Total TCP connections initiated by this host"),
+			counter("Tcp", "PassiveOpens", "netstat_tcp_passive_opens_total",
+				"Total TCP connections accepted by this host"),
+			counter("Tcp", "AttemptFails", "netstat_tcp_attempt_fails_total",
+				"Total failed TCP connection attempts"),
+			counter("Tcp", "EstabResets", "netstat_tcp_established_resets_total",
+				"Total TCP connections reset from the ESTABLISHED or CLOSE-WAIT state"),
+			gauge("Tcp", "CurrEstab", "netstat_tcp_current_established",
+				"Number of TCP connections currently in the ESTABLISHED or CLOSE-WAIT state"),
+			counter("Tcp", "InSegs", "netstat_tcp_in_segments_total",
+				"Total TCP segments received"),
+			counter("Tcp", "OutSegs", "netstat_tcp_out_segments_total",
+				"Total TCP segments sent"),
+			counter("Tcp", "RetransSegs", "netstat_tcp_retransmitted_segments_total",
+				"Total TCP segments retransmitted"),
+			counter("Tcp", "InErrs", "netstat_tcp_in_errors_total",
+				"Total TCP segments received in error"),
+			counter("Tcp", "OutRsts", "netstat_tcp_out_resets_total",
+				"Total TCP segments sent with the RST flag"),
+			counter("Udp", "InDatagrams", "netstat_udp_in_datagrams_total",
+				"Total UDP datagrams delivered"),
+			counter("Udp", "OutDatagrams", "netstat_udp_out_datagrams_total",
+				"Total UDP datagrams sent"),
+			counter("Udp", "NoPorts", "netstat_udp_no_ports_total",
+				"Total UDP datagrams received for a port with no listener"),
+			counter("Udp", "InErrors", "netstat_udp_in_errors_total",
+				"Total UDP datagrams that could not be delivered"),
+			counter("Udp", "RcvbufErrors", "netstat_udp_receive_buffer_errors_total",
+				"Total UDP datagrams dropped because the socket receive buffer was full"),
+			counter("Udp", "SndbufErrors", "netstat_udp_send_buffer_errors_total",
+				"Total UDP datagrams dropped because the socket send buffer was full"),
+
+			// /proc/net/netstat
+			counter("TcpExt", "ListenOverflows", "netstat_tcp_listen_overflows_total",
+				"Total times the accept queue of a listening TCP socket overflowed"),
+			counter("TcpExt", "ListenDrops", "netstat_tcp_listen_drops_total",
+				"Total SYNs to listening TCP sockets dropped"),
+			counter("TcpExt", "TCPTimeouts", "netstat_tcp_timeouts_total",
+				"Total TCP retransmission timeouts"),
+			counter("TcpExt", "TCPSynRetrans", "netstat_tcp_syn_retransmits_total",
+				"Total TCP SYN and SYN/ACK retransmits"),
+			counter("TcpExt", "TCPFastRetrans", "netstat_tcp_fast_retransmits_total",
+				"Total TCP fast retransmits"),
+			counter("TcpExt", "TCPLostRetransmit", "netstat_tcp_lost_retransmits_total",
+				"Total TCP retransmitted segments that were lost again"),
+			counter("TcpExt", "TCPBacklogDrop", "netstat_tcp_backlog_drops_total",
+				"Total TCP packets dropped because the socket backlog was full"),
+			counter("TcpExt", "TCPAbortOnTimeout", "netstat_tcp_abort_on_timeout_total",
+				"Total TCP connections aborted after too many retransmission timeouts"),
+			counter("TcpExt", "SyncookiesSent", "netstat_tcp_syncookies_sent_total",
+				"Total TCP SYN cookies sent"),
+			counter("IpExt", "InOctets", "netstat_ip_in_octets_total",
+				"Total IP octets received"),
+			counter("IpExt", "OutOctets", "netstat_ip_out_octets_total",
+				"Total IP octets sent"),
+		},
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *NetstatCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, f := range c.fields {
+		ch <- f.desc
+	}
+}
+
+// Collect reads protocol statistics and sends the configured fields to the channel.
+// Fields missing from the running kernel are skipped.
+func (c *NetstatCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := make(map[string]map[string]float64)
+	for _, path := range []string{"/proc/net/snmp", "/proc/net/netstat"} {
+		if err := readProcNetStats(path, stats); err != nil {
+			continue
+		}
+	}
+
+	for _, f := range c.fields {
+		v, ok := stats[f.protocol][f.field]
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(f.desc, f.valueType, v)
+	}
+}
+
+// readProcNetStats parses a /proc/net/snmp-style file, where each protocol has a
+// header line of field names followed by a line of values, into stats
+// (protocol -> field -> value).
+func readProcNetStats(path string, stats map[string]map[string]float64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		header := strings.Fields(scanner.Text())
+		if !scanner.Scan() {
+			break
+		}
+		values := strings.Fields(scanner.Text())
+
+		if len(header) == 0 || len(header) != len(values) || header[0] != values[0] {
+			continue
+		}
+
+		protocol := strings.TrimSuffix(header[0], ":")
+		if stats[protocol] == nil {
+			stats[protocol] = make(map[string]float64)
+		}
+		for i := 1; i < len(header); i++ {
+			v, err := strconv.ParseFloat(values[i], 64)
+			if err != nil {
+				continue
+			}
+			stats[protocol][header[i]] = v
+		}
+	}
+
+	return scanner.Err()
+}
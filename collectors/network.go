@@ -1,108 +1,371 @@
 package collectors
 
 import (
+	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-// monitoredInterfaces is the fixed list of network interfaces to monitor on DGX Spark.
-// Only interfaces that are currently "up" will have metrics emitted.
-var monitoredInterfaces = []string{
-	"enP7s7",
-	"enp1s0f1np1",
-	"enP2p1s0f1np1",
-	"enp1s0f0np0",
-	"enP2p1s0f0np0",
-	"wlP9s9",
+// DefaultNetworkInterfaceExclude matches loopback, container veth, and Docker bridge interfaces.
+const DefaultNetworkInterfaceExclude = `^(lo|veth.*|docker.*)$`
+
+// Stable interface identity labels accepted by NewNetworkCollector.
+const (
+	NetworkIDLabelNone    = ""
+	NetworkIDLabelMAC     = "mac"
+	NetworkIDLabelAltName = "altname"
+)
+
+// netStatCounter maps a /sys/class/net/<iface>/statistics file, and the matching
+// rtnl_link_stats64 field for other network namespaces, to an exported counter.
+type netStatCounter struct {
+	file   string
+	stat64 int
+	desc   *prometheus.Desc
 }
 
-// NetworkCollector collects per-interface network I/O counters.
+// NetworkCollector collects per-interface network I/O, error, and drop counters.
 type NetworkCollector struct {
-	rxBytesDesc   *prometheus.Desc
-	txBytesDesc   *prometheus.Desc
-	rxPacketsDesc *prometheus.Desc
-	txPacketsDesc *prometheus.Desc
+	counters           []netStatCounter
+	upDesc             *prometheus.Desc
+	carrierChangesDesc *prometheus.Desc
+	speedDesc          *prometheus.Desc
+	duplexDesc         *prometheus.Desc
+	infoDesc           *prometheus.Desc
+	operstateDesc      *prometheus.Desc
+
+	interfaceInclude *regexp.Regexp
+	interfaceExclude *regexp.Regexp
+	idLabel          string
+	netns            bool
+	wrap             *wrapTracker // nil unless 32-bit wrap adjustment is enabled
 }
 
 // NewNetworkCollector creates a new NetworkCollector.
-func NewNetworkCollector() *NetworkCollector {
-	return &NetworkCollector{
-		rxBytesDesc: prometheus.NewDesc(
-			"network_receive_bytes_total",
-			"Total bytes received on network interface",
-			[]string{"interface"}, nil,
+// Interfaces are discovered from /sys/class/net; only those matching interfaceInclude
+// and not matching interfaceExclude are reported. A nil regexp disables the respective filter.
+// idLabel (NetworkIDLabelMAC or NetworkIDLabelAltName) adds a label identifying the
+// interface independently of its kernel name; NetworkIDLabelNone adds none.
+// If netns is set, interfaces of the named network namespaces in NamedNetnsDir are
+// reported too, with a netns label that is empty for the exporter's own namespace.
+// If wrapAdjust is set, traffic counters that go backwards while they fit in 32 bits
+// are treated as wrapped 32-bit driver counters and reported as monotonic 64-bit values.
+func NewNetworkCollector(interfaceInclude, interfaceExclude *regexp.Regexp, idLabel string, netns, wrapAdjust bool) *NetworkCollector {
+	labels := []string{"interface"}
+	infoLabels := labels // network_interface_info always has a mac label
+	if idLabel != NetworkIDLabelNone {
+		labels = append(labels, idLabel)
+		if idLabel != NetworkIDLabelMAC {
+			infoLabels = labels
+		}
+	}
+	if netns {
+		labels = withLabels(labels, "netns")
+		infoLabels = withLabels(infoLabels, "netns")
+	}
+	counter := func(file string, stat64 int, name, help string) netStatCounter {
+		return netStatCounter{
+			file:   file,
+			stat64: stat64,
+			desc:   prometheus.NewDesc(name, help, labels, nil),
+		}
+	}
+
+	c := &NetworkCollector{
+		counters: []netStatCounter{
+			counter("rx_bytes", linkStatRxBytes, "network_receive_bytes_total",
+				"Total bytes received on network interface"),
+			counter("tx_bytes", linkStatTxBytes, "network_transmit_bytes_total",
+				"Total bytes transmitted on network interface"),
+			counter("rx_packets", linkStatRxPackets, "network_receive_packets_total",
+				"Total packets received on network interface"),
+			counter("tx_packets", linkStatTxPackets, "network_transmit_packets_total",
+				"Total packets transmitted on network interface"),
+			counter("rx_errors", linkStatRxErrors, "network_receive_errors_total",
+				"Total receive errors on network interface"),
+			counter("tx_errors", linkStatTxErrors, "network_transmit_errors_total",
+				"Total transmit errors on network interface"),
+			counter("rx_dropped", linkStatRxDropped, "network_receive_drop_total",
+				"Total received packets dropped on network interface"),
+			counter("tx_dropped", linkStatTxDropped, "network_transmit_drop_total",
+				"Total transmitted packets dropped on network interface"),
+			counter("rx_fifo_errors", linkStatRxFifoErrors, "network_receive_fifo_total",
+				"Total receive FIFO buffer overrun errors on network interface"),
+			counter("tx_fifo_errors", linkStatTxFifoErrors, "network_transmit_fifo_total",
+				"Total transmit FIFO buffer errors on network interface"),
+			counter("rx_frame_errors", linkStatRxFrameErrors, "network_receive_frame_total",
+				"Total received frame alignment errors on network interface"),
+			counter("collisions", linkStatCollisions, "network_collisions_total",
+				"Total collisions on network interface"),
+			counter("multicast", linkStatMulticast, "network_receive_multicast_total",
+				"Total multicast packets received on network interface"),
+		},
+		upDesc: prometheus.NewDesc(
+			"network_up",
+			"Whether the network interface is operationally up (1 = up, 0 = not up)",
+			labels, nil,
 		),
-		txBytesDesc: prometheus.NewDesc(
-			"network_transmit_bytes_total",
-			"Total bytes transmitted on network interface",
-			[]string{"interface"}, nil,
+		carrierChangesDesc: prometheus.NewDesc(
+			"network_carrier_changes_total",
+			"Total number of link carrier state changes on network interface",
+			labels, nil,
 		),
-		rxPacketsDesc: prometheus.NewDesc(
-			"network_receive_packets_total",
-			"Total packets received on network interface",
-			[]string{"interface"}, nil,
+		speedDesc: prometheus.NewDesc(
+			"network_speed_mbps",
+			"Negotiated link speed of network interface in Mbit/s",
+			labels, nil,
 		),
-		txPacketsDesc: prometheus.NewDesc(
-			"network_transmit_packets_total",
-			"Total packets transmitted on network interface",
-			[]string{"interface"}, nil,
+		duplexDesc: prometheus.NewDesc(
+			"network_duplex_info",
+			"Negotiated duplex mode of network interface (always 1)",
+			withLabels(labels, "duplex"), nil,
 		),
+		operstateDesc: prometheus.NewDesc(
+			"network_operstate_info",
+			"RFC 2863 operational state of network interface, e.g. up, down, dormant, lowerlayerdown (always 1)",
+			withLabels(labels, "operstate"), nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			"network_interface_info",
+			"Hardware address, MTU, and assigned addresses of network interface; one series per address (always 1)",
+			withLabels(infoLabels, "mac", "mtu", "address"), nil,
+		),
+		interfaceInclude: interfaceInclude,
+		interfaceExclude: interfaceExclude,
+		idLabel:          idLabel,
+		netns:            netns,
+	}
+	if wrapAdjust {
+		c.wrap = newWrapTracker()
 	}
+	return c
 }
 
 // Describe sends metric descriptors to the channel.
 func (c *NetworkCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.rxBytesDesc
-	ch <- c.txBytesDesc
-	ch <- c.rxPacketsDesc
-	ch <- c.txPacketsDesc
+	for _, s := range c.counters {
+		ch <- s.desc
+	}
+	ch <- c.upDesc
+	ch <- c.carrierChangesDesc
+	ch <- c.speedDesc
+	ch <- c.duplexDesc
+	ch <- c.infoDesc
+	ch <- c.operstateDesc
 }
 
-// Collect reads network interface statistics for monitored interfaces that are up.
+// Collect reports link state and traffic statistics for all monitored interfaces.
+// Counters of down interfaces are reported too, so their series stay continuous.
 func (c *NetworkCollector) Collect(ch chan<- prometheus.Metric) {
-	for _, iface := range monitoredInterfaces {
-		if !isInterfaceUp(iface) {
+	var altNames map[string]string
+	if c.idLabel == NetworkIDLabelAltName {
+		altNames, _ = readInterfaceAltNames()
+	}
+	if c.wrap != nil {
+		c.wrap.begin()
+		defer c.wrap.end()
+	}
+
+	for _, iface := range c.interfaces() {
+		ifaceDir := filepath.Join("/sys/class/net", iface)
+
+		lv := []string{iface}
+		switch c.idLabel {
+		case NetworkIDLabelMAC:
+			lv = append(lv, readSysString(filepath.Join(ifaceDir, "address")))
+		case NetworkIDLabelAltName:
+			lv = append(lv, altNames[iface])
+		}
+		if c.netns {
+			lv = append(lv, "")
+		}
+
+		operstate := readSysString(filepath.Join(ifaceDir, "operstate"))
+		up := operstate == "up"
+		upValue := 0.0
+		if up {
+			upValue = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, upValue, lv...)
+		if operstate != "" {
+			ch <- prometheus.MustNewConstMetric(c.operstateDesc, prometheus.GaugeValue, 1, withLabels(lv, operstate)...)
+		}
+
+		carrierChanges := readSysUint64(filepath.Join(ifaceDir, "carrier_changes"))
+		ch <- prometheus.MustNewConstMetric(c.carrierChangesDesc, prometheus.CounterValue, float64(carrierChanges), lv...)
+
+		c.collectInfo(ch, iface, lv)
+
+		statsDir := filepath.Join(ifaceDir, "statistics")
+		ifindex := int(readSysUint64(filepath.Join(ifaceDir, "ifindex")))
+		for _, s := range c.counters {
+			// A failed read must not reach the wrap tracker as a drop to 0
+			raw, ok := readSysCounter(filepath.Join(statsDir, s.file))
+			if !ok {
+				continue
+			}
+			v := c.adjustWrap("", iface, ifindex, s.file, raw)
+			ch <- prometheus.MustNewConstMetric(s.desc, prometheus.CounterValue, float64(v), lv...)
+		}
+
+		// Speed and duplex are unknown without a link
+		if up {
+			c.collectLinkMode(ch, ifaceDir, lv)
+		}
+	}
+
+	if c.netns {
+		for _, ns := range listNamedNetns() {
+			c.collectNetns(ch, ns)
+		}
+	}
+}
+
+// collectNetns reports link state and traffic counters of the interfaces in the
+// named network namespace. Speed, duplex, and interface info come from sysfs and
+// ioctls of the exporter's own namespace and are not reported.
+func (c *NetworkCollector) collectNetns(ch chan<- prometheus.Metric, ns string) {
+	links, err := readNetnsLinks(ns)
+	if err != nil {
+		return
+	}
+
+	for _, link := range links {
+		if c.interfaceInclude != nil && !c.interfaceInclude.MatchString(link.name) {
+			continue
+		}
+		if c.interfaceExclude != nil && c.interfaceExclude.MatchString(link.name) {
 			continue
 		}
 
-		statsDir := filepath.Join("/sys/class/net", iface, "statistics")
+		lv := []string{link.name}
+		switch c.idLabel {
+		case NetworkIDLabelMAC:
+			lv = append(lv, link.mac)
+		case NetworkIDLabelAltName:
+			lv = append(lv, link.altName)
+		}
+		lv = append(lv, ns)
+
+		upValue := 0.0
+		if link.operstate == "up" {
+			upValue = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, upValue, lv...)
+		if link.operstate != "" {
+			ch <- prometheus.MustNewConstMetric(c.operstateDesc, prometheus.GaugeValue, 1, withLabels(lv, link.operstate)...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.carrierChangesDesc, prometheus.CounterValue, float64(link.carrierChanges), lv...)
 
-		rxBytes := readSysUint64(filepath.Join(statsDir, "rx_bytes"))
-		txBytes := readSysUint64(filepath.Join(statsDir, "tx_bytes"))
-		rxPackets := readSysUint64(filepath.Join(statsDir, "rx_packets"))
-		txPackets := readSysUint64(filepath.Join(statsDir, "tx_packets"))
+		for _, s := range c.counters {
+			if s.stat64 < len(link.stats) {
+				v := c.adjustWrap(ns, link.name, link.index, s.file, link.stats[s.stat64])
+				ch <- prometheus.MustNewConstMetric(s.desc, prometheus.CounterValue, float64(v), lv...)
+			}
+		}
+	}
+}
 
-		ch <- prometheus.MustNewConstMetric(c.rxBytesDesc, prometheus.CounterValue, float64(rxBytes), iface)
-		ch <- prometheus.MustNewConstMetric(c.txBytesDesc, prometheus.CounterValue, float64(txBytes), iface)
-		ch <- prometheus.MustNewConstMetric(c.rxPacketsDesc, prometheus.CounterValue, float64(rxPackets), iface)
-		ch <- prometheus.MustNewConstMetric(c.txPacketsDesc, prometheus.CounterValue, float64(txPackets), iface)
+// adjustWrap returns the traffic counter value v corrected for 32-bit wraps, or v
+// unchanged if wrap adjustment is disabled.
+func (c *NetworkCollector) adjustWrap(netns, iface string, ifindex int, counter string, v uint64) uint64 {
+	if c.wrap == nil {
+		return v
 	}
+	return c.wrap.adjust(wrapKey{netns, iface, counter}, ifindex, v)
 }
 
-// isInterfaceUp checks if a network interface exists and has operstate "up".
-func isInterfaceUp(iface string) bool {
-	path := filepath.Join("/sys/class/net", iface, "operstate")
-	data, err := os.ReadFile(path)
+// collectLinkMode reports negotiated speed and duplex. Virtual interfaces and
+// links without carrier report an unknown speed (-1 or a read error) and are skipped.
+func (c *NetworkCollector) collectLinkMode(ch chan<- prometheus.Metric, ifaceDir string, lv []string) {
+	speed, err := strconv.ParseInt(readSysString(filepath.Join(ifaceDir, "speed")), 10, 64)
+	if err == nil && speed > 0 {
+		ch <- prometheus.MustNewConstMetric(c.speedDesc, prometheus.GaugeValue, float64(speed), lv...)
+	}
+
+	if duplex := readSysString(filepath.Join(ifaceDir, "duplex")); duplex != "" {
+		ch <- prometheus.MustNewConstMetric(c.duplexDesc, prometheus.GaugeValue, 1, withLabels(lv, duplex)...)
+	}
+}
+
+// collectInfo reports the interface's MAC address, MTU, and addresses. An interface
+// without addresses is reported once with an empty address label.
+func (c *NetworkCollector) collectInfo(ch chan<- prometheus.Metric, iface string, lv []string) {
+	ni, err := net.InterfaceByName(iface)
 	if err != nil {
-		return false
+		return
+	}
+	mac := ni.HardwareAddr.String()
+	mtu := strconv.Itoa(ni.MTU)
+	if c.idLabel == NetworkIDLabelMAC {
+		lv = append(lv[:1:1], lv[2:]...)
+	}
+
+	addrs, _ := ni.Addrs()
+	if len(addrs) == 0 {
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, withLabels(lv, mac, mtu, "")...)
+		return
+	}
+	for _, addr := range addrs {
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, withLabels(lv, mac, mtu, addr.String())...)
 	}
-	state := strings.TrimSpace(string(data))
-	return state == "up"
 }
 
-// readSysUint64 reads a sysfs file containing a single uint64 value.
+// withLabels returns a copy of labels with extra appended.
+func withLabels(labels []string, extra ...string) []string {
+	return append(append(make([]string, 0, len(labels)+len(extra)), labels...), extra...)
+}
+
+// interfaces returns the names of all interfaces in /sys/class/net that pass the
+// include/exclude filters.
+func (c *NetworkCollector) interfaces() []string {
+	return listInterfaces(c.interfaceInclude, c.interfaceExclude)
+}
+
+// listInterfaces returns the names of all interfaces in /sys/class/net matching
+// include and not matching exclude. A nil regexp disables the respective filter.
+func listInterfaces(include, exclude *regexp.Regexp) []string {
+	entries, err := os.ReadDir("/sys/class/net")
+	if err != nil {
+		return nil
+	}
+
+	var ifaces []string
+	for _, e := range entries {
+		name := e.Name()
+		if include != nil && !include.MatchString(name) {
+			continue
+		}
+		if exclude != nil && exclude.MatchString(name) {
+			continue
+		}
+		ifaces = append(ifaces, name)
+	}
+	return ifaces
+}
+
+// readSysUint64 reads a sysfs file containing a single uint64 value, returning 0
+// if it cannot be read.
 func readSysUint64(path string) uint64 {
+	v, _ := readSysCounter(path)
+	return v
+}
+
+// readSysCounter reads a sysfs file containing a single uint64 value and reports
+// whether it could be read and parsed.
+func readSysCounter(path string) (uint64, bool) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return 0
+		return 0, false
 	}
 	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
 	if err != nil {
-		return 0
+		return 0, false
 	}
-	return v
+	return v, true
 }
@@ -0,0 +1,169 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// nftRuleset is the subset of "nft -j list ruleset" output used by the collector.
+// Each element of the array holds exactly one object type.
+type nftRuleset struct {
+	Nftables []struct {
+		Rule *struct {
+			Family  string            `json:"family"`
+			Table   string            `json:"table"`
+			Chain   string            `json:"chain"`
+			Handle  int               `json:"handle"`
+			Comment string            `json:"comment"`
+			Expr    []json.RawMessage `json:"expr"`
+		} `json:"rule"`
+		Counter *struct {
+			Family  string  `json:"family"`
+			Table   string  `json:"table"`
+			Name    string  `json:"name"`
+			Packets float64 `json:"packets"`
+			Bytes   float64 `json:"bytes"`
+		} `json:"counter"`
+	} `json:"nftables"`
+}
+
+// nftCounterExpr is an anonymous "counter" statement within a rule expression.
+type nftCounterExpr struct {
+	Counter *struct {
+		Packets float64 `json:"packets"`
+		Bytes   float64 `json:"bytes"`
+	} `json:"counter"`
+}
+
+// NftablesCollector collects packet and byte counters of nftables rules and named
+// counter objects. Rules created through iptables-nft are included.
+type NftablesCollector struct {
+	rulePacketsDesc    *prometheus.Desc
+	ruleBytesDesc      *prometheus.Desc
+	counterPacketsDesc *prometheus.Desc
+	counterBytesDesc   *prometheus.Desc
+
+	nftPath string
+	include *regexp.Regexp
+}
+
+// NewNftablesCollector creates a new NftablesCollector that runs the nft binary at nftPath.
+// Only rules whose "<family>/<table>/<chain>/<rule>" and named counters whose
+// "<family>/<table>/<name>" match include are reported; a nil regexp reports all.
+func NewNftablesCollector(nftPath string, include *regexp.Regexp) *NftablesCollector {
+	ruleLabels := []string{"family", "table", "chain", "rule"}
+	counterLabels := []string{"family", "table", "name"}
+	return &NftablesCollector{
+		rulePacketsDesc: prometheus.NewDesc(
+			"nftables_rule_packets_total",
+			"Total packets matched by the nftables rule counter (rule = comment, or handle if none)",
+			ruleLabels, nil,
+		),
+		ruleBytesDesc: prometheus.NewDesc(
+			"nftables_rule_bytes_total",
+			"Total bytes matched by the nftables rule counter (rule = comment, or handle if none)",
+			ruleLabels, nil,
+		),
+		counterPacketsDesc: prometheus.NewDesc(
+			"nftables_counter_packets_total",
+			"Total packets of the named nftables counter",
+			counterLabels, nil,
+		),
+		counterBytesDesc: prometheus.NewDesc(
+			"nftables_counter_bytes_total",
+			"Total bytes of the named nftables counter",
+			counterLabels, nil,
+		),
+		nftPath: nftPath,
+		include: include,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *NftablesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.rulePacketsDesc
+	ch <- c.ruleBytesDesc
+	ch <- c.counterPacketsDesc
+	ch <- c.counterBytesDesc
+}
+
+// Collect runs "nft -j list ruleset" and sends the counters to the channel.
+// Rules without a counter statement are skipped. If nft is not installed or fails,
+// no metrics are emitted.
+func (c *NftablesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing nft when ctx is done.
+func (c *NftablesCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	out, err := exec.CommandContext(ctx, c.nftPath, "-j", "list", "ruleset").Output()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.rulePacketsDesc, fmt.Errorf("nft failed: %w", err))
+		return
+	}
+
+	var ruleset nftRuleset
+	if err := json.Unmarshal(out, &ruleset); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.rulePacketsDesc, fmt.Errorf("nft: unexpected output format: %w", err))
+		return
+	}
+
+	// Rules sharing a comment within a chain are summed, so a comment can label a
+	// group of rules (e.g. all drops of cluster traffic)
+	type ruleKey struct{ family, table, chain, rule string }
+	type ruleCounts struct{ packets, bytes float64 }
+	var order []ruleKey
+	rules := make(map[ruleKey]*ruleCounts)
+
+	for _, obj := range ruleset.Nftables {
+		if r := obj.Rule; r != nil {
+			key := ruleKey{r.Family, r.Table, r.Chain, r.Comment}
+			if key.rule == "" {
+				key.rule = "handle:" + strconv.Itoa(r.Handle)
+			}
+			if !c.included(key.family + "/" + key.table + "/" + key.chain + "/" + key.rule) {
+				continue
+			}
+
+			for _, raw := range r.Expr {
+				var e nftCounterExpr
+				if json.Unmarshal(raw, &e) != nil || e.Counter == nil {
+					continue
+				}
+				counts, ok := rules[key]
+				if !ok {
+					counts = &ruleCounts{}
+					rules[key] = counts
+					order = append(order, key)
+				}
+				counts.packets += e.Counter.Packets
+				counts.bytes += e.Counter.Bytes
+			}
+		}
+
+		if n := obj.Counter; n != nil {
+			if !c.included(n.Family + "/" + n.Table + "/" + n.Name) {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.counterPacketsDesc, prometheus.CounterValue, n.Packets, n.Family, n.Table, n.Name)
+			ch <- prometheus.MustNewConstMetric(c.counterBytesDesc, prometheus.CounterValue, n.Bytes, n.Family, n.Table, n.Name)
+		}
+	}
+
+	for _, key := range order {
+		counts := rules[key]
+		ch <- prometheus.MustNewConstMetric(c.rulePacketsDesc, prometheus.CounterValue, counts.packets, key.family, key.table, key.chain, key.rule)
+		ch <- prometheus.MustNewConstMetric(c.ruleBytesDesc, prometheus.CounterValue, counts.bytes, key.family, key.table, key.chain, key.rule)
+	}
+}
+
+// included reports whether a rule or counter path passes the include filter.
+func (c *NftablesCollector) included(path string) bool {
+	return c.include == nil || c.include.MatchString(path)
+}
@@ -0,0 +1,242 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// nvmeIoctlAdminCmd is NVME_IOCTL_ADMIN_CMD, _IOWR('N', 0x41, struct nvme_passthru_cmd).
+	nvmeIoctlAdminCmd = 0xC0484E41
+
+	nvmeAdminGetLogPage = 0x02
+	nvmeLogSMART        = 0x02
+	nvmeSMARTLogSize    = 512
+	nvmeNSIDAll         = 0xFFFFFFFF
+
+	// nvmeDataUnitBytes is the size of the SMART "data units" counters (1000 * 512 bytes).
+	nvmeDataUnitBytes = 512000
+)
+
+// nvmePassthruCmd mirrors struct nvme_passthru_cmd from <linux/nvme_ioctl.h>.
+type nvmePassthruCmd struct {
+	opcode      uint8
+	flags       uint8
+	rsvd1       uint16
+	nsid        uint32
+	cdw2        uint32
+	cdw3        uint32
+	metadata    uint64
+	addr        uint64
+	metadataLen uint32
+	dataLen     uint32
+	cdw10       uint32
+	cdw11       uint32
+	cdw12       uint32
+	cdw13       uint32
+	cdw14       uint32
+	cdw15       uint32
+	timeoutMs   uint32
+	result      uint32
+}
+
+// NVMeCollector collects NVMe SMART/health log metrics using admin passthrough ioctls.
+type NVMeCollector struct {
+	infoDesc             *prometheus.Desc
+	criticalWarningDesc  *prometheus.Desc
+	tempDesc             *prometheus.Desc
+	availSpareDesc       *prometheus.Desc
+	availSpareThreshDesc *prometheus.Desc
+	percentUsedDesc      *prometheus.Desc
+	dataReadDesc         *prometheus.Desc
+	dataWrittenDesc      *prometheus.Desc
+	powerCyclesDesc      *prometheus.Desc
+	powerOnHoursDesc     *prometheus.Desc
+	unsafeShutdownsDesc  *prometheus.Desc
+	mediaErrorsDesc      *prometheus.Desc
+}
+
+// NewNVMeCollector creates a new NVMeCollector.
+func NewNVMeCollector() *NVMeCollector {
+	labels := []string{"device"}
+	return &NVMeCollector{
+		infoDesc: prometheus.NewDesc(
+			"nvme_info",
+			"NVMe controller information (always 1)",
+			[]string{"device", "model", "serial", "firmware"}, nil,
+		),
+		criticalWarningDesc: prometheus.NewDesc(
+			"nvme_critical_warning",
+			"NVMe SMART critical warning bit field (0 = no warnings)",
+			labels, nil,
+		),
+		tempDesc: prometheus.NewDesc(
+			"nvme_temperature_celsius",
+			"NVMe composite temperature in degrees Celsius",
+			labels, nil,
+		),
+		availSpareDesc: prometheus.NewDesc(
+			"nvme_available_spare_percent",
+			"NVMe remaining spare capacity in percent",
+			labels, nil,
+		),
+		availSpareThreshDesc: prometheus.NewDesc(
+			"nvme_available_spare_threshold_percent",
+			"NVMe available spare threshold in percent, below which a critical warning is raised",
+			labels, nil,
+		),
+		percentUsedDesc: prometheus.NewDesc(
+			"nvme_percentage_used",
+			"NVMe vendor estimate of endurance used in percent (may exceed 100)",
+			labels, nil,
+		),
+		dataReadDesc: prometheus.NewDesc(
+			"nvme_data_read_bytes_total",
+			"Total bytes read by the host as reported by the NVMe SMART log",
+			labels, nil,
+		),
+		dataWrittenDesc: prometheus.NewDesc(
+			"nvme_data_written_bytes_total",
+			"Total bytes written by the host as reported by the NVMe SMART log",
+			labels, nil,
+		),
+		powerCyclesDesc: prometheus.NewDesc(
+			"nvme_power_cycles_total",
+			"Total number of NVMe controller power cycles",
+			labels, nil,
+		),
+		powerOnHoursDesc: prometheus.NewDesc(
+			"nvme_power_on_hours_total",
+			"Total number of NVMe power-on hours",
+			labels, nil,
+		),
+		unsafeShutdownsDesc: prometheus.NewDesc(
+			"nvme_unsafe_shutdowns_total",
+			"Total number of NVMe unsafe shutdowns",
+			labels, nil,
+		),
+		mediaErrorsDesc: prometheus.NewDesc(
+			"nvme_media_errors_total",
+			"Total number of NVMe unrecovered data integrity errors",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *NVMeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.criticalWarningDesc
+	ch <- c.tempDesc
+	ch <- c.availSpareDesc
+	ch <- c.availSpareThreshDesc
+	ch <- c.percentUsedDesc
+	ch <- c.dataReadDesc
+	ch <- c.dataWrittenDesc
+	ch <- c.powerCyclesDesc
+	ch <- c.powerOnHoursDesc
+	ch <- c.unsafeShutdownsDesc
+	ch <- c.mediaErrorsDesc
+}
+
+// Collect reads the SMART/health log of every NVMe controller and sends it to the channel.
+// Controllers that cannot be opened (e.g. when not running as root) are skipped.
+func (c *NVMeCollector) Collect(ch chan<- prometheus.Metric) {
+	controllers, err := filepath.Glob("/sys/class/nvme/nvme*")
+	if err != nil {
+		return
+	}
+
+	for _, sysPath := range controllers {
+		device := filepath.Base(sysPath)
+
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1,
+			device,
+			readSysString(filepath.Join(sysPath, "model")),
+			readSysString(filepath.Join(sysPath, "serial")),
+			readSysString(filepath.Join(sysPath, "firmware_rev")),
+		)
+
+		log, err := readNVMeSMARTLog(filepath.Join("/dev", device))
+		if err != nil {
+			continue
+		}
+
+		gauge := func(desc *prometheus.Desc, v float64) {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, device)
+		}
+		counter := func(desc *prometheus.Desc, v float64) {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v, device)
+		}
+
+		// Byte offsets per NVMe Base Specification, SMART / Health Information log page
+		gauge(c.criticalWarningDesc, float64(log[0]))
+		gauge(c.tempDesc, float64(binary.LittleEndian.Uint16(log[1:3]))-273.15)
+		gauge(c.availSpareDesc, float64(log[3]))
+		gauge(c.availSpareThreshDesc, float64(log[4]))
+		gauge(c.percentUsedDesc, float64(log[5]))
+		counter(c.dataReadDesc, readUint128LE(log[32:48])*nvmeDataUnitBytes)
+		counter(c.dataWrittenDesc, readUint128LE(log[48:64])*nvmeDataUnitBytes)
+		counter(c.powerCyclesDesc, readUint128LE(log[112:128]))
+		counter(c.powerOnHoursDesc, readUint128LE(log[128:144]))
+		counter(c.unsafeShutdownsDesc, readUint128LE(log[144:160]))
+		counter(c.mediaErrorsDesc, readUint128LE(log[160:176]))
+	}
+}
+
+// readNVMeSMARTLog issues a Get Log Page admin command for the SMART/health log
+// on the given NVMe controller character device.
+func readNVMeSMARTLog(devPath string) ([]byte, error) {
+	f, err := os.Open(devPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, nvmeSMARTLogSize)
+
+	// The kernel writes into buf through the address stored in the command
+	var pinner runtime.Pinner
+	pinner.Pin(&buf[0])
+	defer pinner.Unpin()
+
+	numDwords := uint32(nvmeSMARTLogSize/4 - 1)
+	cmd := nvmePassthruCmd{
+		opcode:  nvmeAdminGetLogPage,
+		nsid:    nvmeNSIDAll,
+		addr:    uint64(uintptr(unsafe.Pointer(&buf[0]))),
+		dataLen: nvmeSMARTLogSize,
+		cdw10:   numDwords<<16 | nvmeLogSMART,
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), nvmeIoctlAdminCmd, uintptr(unsafe.Pointer(&cmd)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return buf, nil
+}
+
+// readUint128LE decodes a little-endian 128-bit counter as a float64.
+func readUint128LE(b []byte) float64 {
+	lo := binary.LittleEndian.Uint64(b[0:8])
+	hi := binary.LittleEndian.Uint64(b[8:16])
+	return float64(hi)*math.Pow(2, 64) + float64(lo)
+}
+
+// readSysString reads a sysfs attribute and returns its trimmed content,
+// or an empty string if it cannot be read.
+func readSysString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,245 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NVMe over Fabrics sources. The target is configured through configfs; connected
+// hosts are only listed in debugfs (Linux 6.14+, root only).
+const (
+	nvmeClassDir    = "/sys/class/nvme"
+	nvmetConfigDir  = "/sys/kernel/config/nvmet"
+	nvmetDebugDir   = "/sys/kernel/debug/nvmet"
+	blockClassDir   = "/sys/class/block"
+	blockSectorSize = 512
+)
+
+// nvmeControllerStates are the states of an NVMe host controller
+// (nvme_ctrl_state_names in drivers/nvme/host/core.c).
+var nvmeControllerStates = []string{"new", "live", "resetting", "connecting", "deleting", "deleting (no IO)", "dead"}
+
+// nvmeNamespaceRe matches the namespace block devices of a controller: nvme1n1, or
+// nvme1c1n1 for a path of a multipath namespace.
+var nvmeNamespaceRe = regexp.MustCompile(`^nvme\d+(?:c\d+)?n\d+$`)
+
+// NVMeoFCollector collects the state and I/O of NVMe over Fabrics connections:
+// controllers this host connected to as an initiator, and subsystems it exports as
+// an nvmet target, e.g. over the 200GbE link between two Sparks.
+type NVMeoFCollector struct {
+	controllerInfoDesc  *prometheus.Desc
+	controllerStateDesc *prometheus.Desc
+	readBytesDesc       *prometheus.Desc
+	writtenBytesDesc    *prometheus.Desc
+	readsDesc           *prometheus.Desc
+	writesDesc          *prometheus.Desc
+
+	targetPortDesc       *prometheus.Desc
+	targetNamespaceDesc  *prometheus.Desc
+	targetHostsDesc      *prometheus.Desc
+	targetReadBytesDesc  *prometheus.Desc
+	targetWriteBytesDesc *prometheus.Desc
+}
+
+// NewNVMeoFCollector creates a new NVMeoFCollector.
+func NewNVMeoFCollector() *NVMeoFCollector {
+	nsLabels := []string{"controller", "namespace"}
+	targetNSLabels := []string{"subsystem", "namespace"}
+	return &NVMeoFCollector{
+		controllerInfoDesc: prometheus.NewDesc(
+			"nvmeof_controller_info",
+			"NVMe over Fabrics controller connected by this host, always 1",
+			[]string{"controller", "transport", "address", "subsystem"}, nil,
+		),
+		controllerStateDesc: prometheus.NewDesc(
+			"nvmeof_controller_state",
+			"Whether the NVMe over Fabrics controller is in the given state (1 = current state)",
+			[]string{"controller", "state"}, nil,
+		),
+		readBytesDesc: prometheus.NewDesc(
+			"nvmeof_namespace_read_bytes_total",
+			"Total bytes read from the remote namespace",
+			nsLabels, nil,
+		),
+		writtenBytesDesc: prometheus.NewDesc(
+			"nvmeof_namespace_written_bytes_total",
+			"Total bytes written to the remote namespace",
+			nsLabels, nil,
+		),
+		readsDesc: prometheus.NewDesc(
+			"nvmeof_namespace_reads_completed_total",
+			"Total reads completed on the remote namespace",
+			nsLabels, nil,
+		),
+		writesDesc: prometheus.NewDesc(
+			"nvmeof_namespace_writes_completed_total",
+			"Total writes completed on the remote namespace",
+			nsLabels, nil,
+		),
+		targetPortDesc: prometheus.NewDesc(
+			"nvmet_port_info",
+			"NVMe target port, always 1",
+			[]string{"port", "transport", "address", "service_id"}, nil,
+		),
+		targetNamespaceDesc: prometheus.NewDesc(
+			"nvmet_namespace_enabled",
+			"Whether the namespace exported by the NVMe target is enabled (1 = enabled)",
+			[]string{"subsystem", "namespace", "device_path"}, nil,
+		),
+		targetHostsDesc: prometheus.NewDesc(
+			"nvmet_subsystem_controllers",
+			"Number of host controllers connected to the NVMe target subsystem",
+			[]string{"subsystem"}, nil,
+		),
+		targetReadBytesDesc: prometheus.NewDesc(
+			"nvmet_namespace_read_bytes_total",
+			"Total bytes read from the block device backing the exported namespace, including local I/O",
+			targetNSLabels, nil,
+		),
+		targetWriteBytesDesc: prometheus.NewDesc(
+			"nvmet_namespace_written_bytes_total",
+			"Total bytes written to the block device backing the exported namespace, including local I/O",
+			targetNSLabels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *NVMeoFCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.controllerInfoDesc
+	ch <- c.controllerStateDesc
+	ch <- c.readBytesDesc
+	ch <- c.writtenBytesDesc
+	ch <- c.readsDesc
+	ch <- c.writesDesc
+	ch <- c.targetPortDesc
+	ch <- c.targetNamespaceDesc
+	ch <- c.targetHostsDesc
+	ch <- c.targetReadBytesDesc
+	ch <- c.targetWriteBytesDesc
+}
+
+// Collect sends the initiator and target state to the channel. Local PCIe
+// controllers are left to the NVMe collector.
+func (c *NVMeoFCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectInitiator(ch)
+	c.collectTarget(ch)
+}
+
+// collectInitiator reports the fabrics controllers in /sys/class/nvme and the I/O
+// of their namespaces.
+func (c *NVMeoFCollector) collectInitiator(ch chan<- prometheus.Metric) {
+	controllers, _ := filepath.Glob(filepath.Join(nvmeClassDir, "nvme*"))
+	for _, dir := range controllers {
+		transport := readSysString(filepath.Join(dir, "transport"))
+		if transport == "" || transport == "pcie" {
+			continue
+		}
+		ctrl := filepath.Base(dir)
+
+		ch <- prometheus.MustNewConstMetric(c.controllerInfoDesc, prometheus.GaugeValue, 1,
+			ctrl,
+			transport,
+			readSysString(filepath.Join(dir, "address")),
+			readSysString(filepath.Join(dir, "subsysnqn")),
+		)
+
+		state := readSysString(filepath.Join(dir, "state"))
+		for _, s := range nvmeControllerStates {
+			v := 0.0
+			if s == state {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.controllerStateDesc, prometheus.GaugeValue, v, ctrl, s)
+		}
+
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if !nvmeNamespaceRe.MatchString(e.Name()) {
+				continue
+			}
+			stat, ok := readBlockStat(filepath.Join(dir, e.Name(), "stat"))
+			if !ok {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.readsDesc, prometheus.CounterValue, stat[0], ctrl, e.Name())
+			ch <- prometheus.MustNewConstMetric(c.readBytesDesc, prometheus.CounterValue, stat[2]*blockSectorSize, ctrl, e.Name())
+			ch <- prometheus.MustNewConstMetric(c.writesDesc, prometheus.CounterValue, stat[4], ctrl, e.Name())
+			ch <- prometheus.MustNewConstMetric(c.writtenBytesDesc, prometheus.CounterValue, stat[6]*blockSectorSize, ctrl, e.Name())
+		}
+	}
+}
+
+// collectTarget reports the ports and subsystems of the nvmet target configured in
+// configfs. The target keeps no I/O statistics of its own, so the statistics of
+// the block devices backing the namespaces are reported instead.
+func (c *NVMeoFCollector) collectTarget(ch chan<- prometheus.Metric) {
+	ports, _ := os.ReadDir(filepath.Join(nvmetConfigDir, "ports"))
+	for _, p := range ports {
+		dir := filepath.Join(nvmetConfigDir, "ports", p.Name())
+		ch <- prometheus.MustNewConstMetric(c.targetPortDesc, prometheus.GaugeValue, 1,
+			p.Name(),
+			readSysString(filepath.Join(dir, "addr_trtype")),
+			readSysString(filepath.Join(dir, "addr_traddr")),
+			readSysString(filepath.Join(dir, "addr_trsvcid")),
+		)
+	}
+
+	subsystems, _ := os.ReadDir(filepath.Join(nvmetConfigDir, "subsystems"))
+	for _, s := range subsystems {
+		nqn := s.Name()
+		namespaces, _ := os.ReadDir(filepath.Join(nvmetConfigDir, "subsystems", nqn, "namespaces"))
+		for _, ns := range namespaces {
+			dir := filepath.Join(nvmetConfigDir, "subsystems", nqn, "namespaces", ns.Name())
+			devicePath := readSysString(filepath.Join(dir, "device_path"))
+			enabled, _ := readProcSysFloat(filepath.Join(dir, "enable"))
+			ch <- prometheus.MustNewConstMetric(c.targetNamespaceDesc, prometheus.GaugeValue, enabled, nqn, ns.Name(), devicePath)
+
+			if !strings.HasPrefix(devicePath, "/dev/") {
+				continue
+			}
+			// Resolve /dev/disk/by-* and device-mapper symlinks to the kernel name
+			dev, err := filepath.EvalSymlinks(devicePath)
+			if err != nil {
+				continue
+			}
+			if stat, ok := readBlockStat(filepath.Join(blockClassDir, filepath.Base(dev), "stat")); ok {
+				ch <- prometheus.MustNewConstMetric(c.targetReadBytesDesc, prometheus.CounterValue, stat[2]*blockSectorSize, nqn, ns.Name())
+				ch <- prometheus.MustNewConstMetric(c.targetWriteBytesDesc, prometheus.CounterValue, stat[6]*blockSectorSize, nqn, ns.Name())
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(nvmetDebugDir, nqn)); err == nil {
+			ctrls, _ := filepath.Glob(filepath.Join(nvmetDebugDir, nqn, "ctrl*"))
+			ch <- prometheus.MustNewConstMetric(c.targetHostsDesc, prometheus.GaugeValue, float64(len(ctrls)), nqn)
+		}
+	}
+}
+
+// readBlockStat parses a block device stat file (Documentation/block/stat.rst).
+// Indices 0, 2, 4, and 6 are reads completed, sectors read, writes completed, and
+// sectors written.
+func readBlockStat(path string) ([]float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 7 {
+		return nil, false
+	}
+	stat := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, false
+		}
+		stat[i] = v
+	}
+	return stat, true
+}
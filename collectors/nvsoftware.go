@@ -0,0 +1,175 @@
+package collectors
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Software version sources.
+const (
+	nvidiaModuleVersionFile = "/sys/module/nvidia/version"
+	cudaVersionFile         = "/usr/local/cuda/version.json"
+	dpkgStatusFile          = "/var/lib/dpkg/status"
+)
+
+// nvidiaPackageComponents maps installed Debian packages to software components.
+var nvidiaPackageComponents = []struct {
+	re        *regexp.Regexp
+	component string
+}{
+	{regexp.MustCompile(`^nvidia-driver-\d+(?:-open|-server|-server-open)?$`), "driver_package"},
+	{regexp.MustCompile(`^nvidia-container-toolkit$`), "container_toolkit"},
+}
+
+// NVIDIASoftwareCollector exports the installed versions of the NVIDIA software stack.
+type NVIDIASoftwareCollector struct {
+	infoDesc *prometheus.Desc
+
+	mu        sync.Mutex
+	dpkgMtime time.Time
+	packages  map[string]string // component -> version, cached until dpkg status changes
+}
+
+// NewNVIDIASoftwareCollector creates a new NVIDIASoftwareCollector.
+func NewNVIDIASoftwareCollector() *NVIDIASoftwareCollector {
+	return &NVIDIASoftwareCollector{
+		infoDesc: prometheus.NewDesc(
+			"nvidia_software_info",
+			"Installed NVIDIA software component version, always 1",
+			[]string{"component", "version"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *NVIDIASoftwareCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+}
+
+// Collect sends the versions of the loaded kernel driver, the CUDA toolkit that
+// /usr/local/cuda points to, the driver and container toolkit packages, and DGX OS
+// to the channel. Components that are not installed are omitted.
+func (c *NVIDIASoftwareCollector) Collect(ch chan<- prometheus.Metric) {
+	versions := make(map[string]string)
+
+	// The loaded module can differ from the installed package until the next reboot
+	versions["driver"] = readSysString(nvidiaModuleVersionFile)
+	versions["cuda_toolkit"] = readCUDAVersion(cudaVersionFile)
+	versions["dgx_os"] = readDGXOSVersion()
+	for component, version := range c.readPackages() {
+		versions[component] = version
+	}
+
+	for component, version := range versions {
+		if version == "" {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, component, version)
+	}
+}
+
+// readPackages returns the versions of the NVIDIA packages installed with dpkg,
+// re-reading the dpkg status database only when it has changed.
+func (c *NVIDIASoftwareCollector) readPackages() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(dpkgStatusFile)
+	if err != nil {
+		return nil
+	}
+	if c.packages != nil && info.ModTime().Equal(c.dpkgMtime) {
+		return c.packages
+	}
+
+	packages, err := readDpkgPackages(func(name string) bool {
+		for _, p := range nvidiaPackageComponents {
+			if p.re.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil
+	}
+
+	components := make(map[string]string)
+	for name, version := range packages {
+		for _, p := range nvidiaPackageComponents {
+			if p.re.MatchString(name) {
+				components[p.component] = version
+			}
+		}
+	}
+	c.packages = components
+	c.dpkgMtime = info.ModTime()
+	return components
+}
+
+// readDpkgPackages returns the versions of the installed packages whose name
+// matches, keyed by package name.
+func readDpkgPackages(match func(name string) bool) (map[string]string, error) {
+	f, err := os.Open(dpkgStatusFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	packages := make(map[string]string)
+	var name, status, version string
+	flush := func() {
+		if name != "" && status == "install ok installed" && match(name) {
+			packages[name] = version
+		}
+		name, status, version = "", "", ""
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Package":
+			name = value
+		case "Status":
+			status = value
+		case "Version":
+			version = value
+		}
+	}
+	flush()
+	return packages, scanner.Err()
+}
+
+// readCUDAVersion returns the CUDA toolkit version from a version.json file.
+func readCUDAVersion(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var v struct {
+		CUDA struct {
+			Version string `json:"version"`
+		} `json:"cuda"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return ""
+	}
+	return v.CUDA.Version
+}
@@ -0,0 +1,98 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OS release files. /etc/os-release falls back to /usr/lib/os-release as per
+// os-release(5); /etc/dgx-release is only present on DGX OS.
+var (
+	osReleaseFiles = []string{"/etc/os-release", "/usr/lib/os-release"}
+	dgxReleaseFile = "/etc/dgx-release"
+)
+
+// OSInfoCollector exports the operating system, DGX OS, and kernel versions.
+type OSInfoCollector struct {
+	infoDesc *prometheus.Desc
+}
+
+// NewOSInfoCollector creates a new OSInfoCollector.
+func NewOSInfoCollector() *OSInfoCollector {
+	return &OSInfoCollector{
+		infoDesc: prometheus.NewDesc(
+			"os_info",
+			"Operating system and kernel version, always 1",
+			[]string{"pretty_name", "kernel", "dgx_os_version"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *OSInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+}
+
+// Collect reads the release files and the running kernel version and sends the
+// info metric to the channel. The files are re-read on every scrape so upgrades
+// show up without restarting the exporter.
+func (c *OSInfoCollector) Collect(ch chan<- prometheus.Metric) {
+	var osRelease map[string]string
+	for _, path := range osReleaseFiles {
+		if vars, err := readEnvFile(path); err == nil {
+			osRelease = vars
+			break
+		}
+	}
+
+	kernel := readSysString("/proc/sys/kernel/osrelease")
+
+	ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, osRelease["PRETTY_NAME"], kernel, readDGXOSVersion())
+}
+
+// readDGXOSVersion returns the DGX OS version, or an empty string if not running
+// DGX OS. OTA updates bump DGX_OTA_VERSION; DGX_SWBUILD_VERSION is the installed image.
+func readDGXOSVersion() string {
+	vars, err := readEnvFile(dgxReleaseFile)
+	if err != nil {
+		return ""
+	}
+	if v := vars["DGX_OTA_VERSION"]; v != "" {
+		return v
+	}
+	return vars["DGX_SWBUILD_VERSION"]
+}
+
+// readEnvFile parses a file of shell-style KEY=value assignments such as
+// /etc/os-release, removing quotes around values.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `"'`)
+		}
+		vars[key] = value
+	}
+	return vars, scanner.Err()
+}
@@ -0,0 +1,132 @@
+package collectors
+
+import (
+	"net"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PeerCollector checks the health of the other node of a dual-Spark cluster: its
+// reachability and round-trip time, and the state of the local interface the peer
+// is reached through, normally the direct ConnectX link.
+type PeerCollector struct {
+	upDesc        *prometheus.Desc
+	rttDesc       *prometheus.Desc
+	linkInfoDesc  *prometheus.Desc
+	linkUpDesc    *prometheus.Desc
+	linkSpeedDesc *prometheus.Desc
+
+	peer    string
+	timeout time.Duration
+	count   int
+}
+
+// NewPeerCollector creates a new PeerCollector for the peer host name or address.
+// Each scrape sends count ICMP echo requests, waiting at most timeout for each,
+// which needs a raw socket (root or CAP_NET_RAW).
+func NewPeerCollector(peer string, timeout time.Duration, count int) *PeerCollector {
+	labels := []string{"peer"}
+	linkLabels := []string{"peer", "interface"}
+	return &PeerCollector{
+		upDesc: prometheus.NewDesc(
+			"peer_up",
+			"Whether the peer node answered at least one ICMP echo request (1 = reachable)",
+			labels, nil,
+		),
+		rttDesc: prometheus.NewDesc(
+			"peer_rtt_seconds",
+			"Average ICMP round-trip time to the peer node in seconds",
+			labels, nil,
+		),
+		linkInfoDesc: prometheus.NewDesc(
+			"peer_link_info",
+			"Local interface and address the peer node is reached through, always 1",
+			[]string{"peer", "interface", "local_address", "peer_address"}, nil,
+		),
+		linkUpDesc: prometheus.NewDesc(
+			"peer_link_up",
+			"Whether the interface towards the peer node is operationally up (1 = up)",
+			linkLabels, nil,
+		),
+		linkSpeedDesc: prometheus.NewDesc(
+			"peer_link_speed_mbps",
+			"Negotiated link speed of the interface towards the peer node in Mbit/s",
+			linkLabels, nil,
+		),
+		peer:    peer,
+		timeout: timeout,
+		count:   count,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *PeerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upDesc
+	ch <- c.rttDesc
+	ch <- c.linkInfoDesc
+	ch <- c.linkUpDesc
+	ch <- c.linkSpeedDesc
+}
+
+// Collect pings the peer, looks up the interface of the route to it, and sends the
+// results to the channel. Link metrics are omitted if the peer cannot be resolved
+// or there is no route to it.
+func (c *PeerCollector) Collect(ch chan<- prometheus.Metric) {
+	up := 0.0
+	if rtts := pingICMP(c.peer, c.count, c.timeout); len(rtts) > 0 {
+		up = 1
+		var sum time.Duration
+		for _, rtt := range rtts {
+			sum += rtt
+		}
+		ch <- prometheus.MustNewConstMetric(c.rttDesc, prometheus.GaugeValue, (sum / time.Duration(len(rtts))).Seconds(), c.peer)
+	}
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, up, c.peer)
+
+	iface, local, remote, ok := routeInterface(c.peer)
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.linkInfoDesc, prometheus.GaugeValue, 1, c.peer, iface, local.String(), remote.String())
+
+	dir := filepath.Join("/sys/class/net", iface)
+	linkUp := 0.0
+	if readSysString(filepath.Join(dir, "operstate")) == "up" {
+		linkUp = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.linkUpDesc, prometheus.GaugeValue, linkUp, c.peer, iface)
+
+	if speed, err := strconv.ParseInt(readSysString(filepath.Join(dir, "speed")), 10, 64); err == nil && speed > 0 {
+		ch <- prometheus.MustNewConstMetric(c.linkSpeedDesc, prometheus.GaugeValue, float64(speed), c.peer, iface)
+	}
+}
+
+// routeInterface returns the local interface and source address the kernel routes
+// traffic to host through, and the resolved address of host. Connecting a UDP socket
+// performs the route lookup without sending a packet.
+func routeInterface(host string) (iface string, local, remote net.IP, ok bool) {
+	conn, err := net.Dial("udp", net.JoinHostPort(host, "9"))
+	if err != nil {
+		return "", nil, nil, false
+	}
+	defer conn.Close()
+	local = conn.LocalAddr().(*net.UDPAddr).IP
+	remote = conn.RemoteAddr().(*net.UDPAddr).IP
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", nil, nil, false
+	}
+	for _, ni := range ifaces {
+		addrs, _ := ni.Addrs()
+		for _, addr := range addrs {
+			if ipnet, isNet := addr.(*net.IPNet); isNet && ipnet.IP.Equal(local) {
+				return ni.Name, local, remote, true
+			}
+		}
+	}
+	return "", nil, nil, false
+}
@@ -0,0 +1,119 @@
+package collectors
+
+import (
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pfcPriorityStat matches mlx5 per-priority flow control statistics, e.g.
+// rx_prio3_pause, tx_prio3_pause_duration, rx_prio3_pause_transition.
+var pfcPriorityStat = regexp.MustCompile(`^(rx|tx)_prio(\d)_pause(?:_(duration|transition))?$`)
+
+// pausePortStats maps driver statistics of link-level (global) pause frames to their direction.
+var pausePortStats = map[string]string{
+	"rx_pause_ctrl_phy": "rx", // mlx5
+	"tx_pause_ctrl_phy": "tx",
+	"rx_pause":          "rx", // ixgbe, i40e, ...
+	"tx_pause":          "tx",
+}
+
+// pauseStormStats maps mlx5 pause storm prevention statistics to their level.
+var pauseStormStats = map[string]string{
+	"tx_pause_storm_warning_events": "warning",
+	"tx_pause_storm_error_events":   "error",
+}
+
+// PFCCollector collects priority flow control (PFC) and link-level pause frame
+// counters of RoCE NICs from ethtool driver statistics.
+type PFCCollector struct {
+	pfcFramesDesc      *prometheus.Desc
+	pfcDurationDesc    *prometheus.Desc
+	pfcTransitionsDesc *prometheus.Desc
+	pauseFramesDesc    *prometheus.Desc
+	stormEventsDesc    *prometheus.Desc
+
+	interfaceInclude *regexp.Regexp
+	interfaceExclude *regexp.Regexp
+}
+
+// NewPFCCollector creates a new PFCCollector.
+// Interfaces are filtered like in NetworkCollector. A nil regexp disables the respective filter.
+func NewPFCCollector(interfaceInclude, interfaceExclude *regexp.Regexp) *PFCCollector {
+	priorityLabels := []string{"interface", "direction", "priority"}
+	return &PFCCollector{
+		pfcFramesDesc: prometheus.NewDesc(
+			"network_pfc_pause_frames_total",
+			"Total PFC pause frames per priority on network interface",
+			priorityLabels, nil,
+		),
+		pfcDurationDesc: prometheus.NewDesc(
+			"network_pfc_pause_duration_seconds_total",
+			"Total time traffic of the priority was paused by PFC on network interface in seconds",
+			priorityLabels, nil,
+		),
+		pfcTransitionsDesc: prometheus.NewDesc(
+			"network_pfc_pause_transitions_total",
+			"Total transitions from XON to XOFF of the priority on network interface",
+			priorityLabels, nil,
+		),
+		pauseFramesDesc: prometheus.NewDesc(
+			"network_pause_frames_total",
+			"Total link-level (global) pause frames on network interface",
+			[]string{"interface", "direction"}, nil,
+		),
+		stormEventsDesc: prometheus.NewDesc(
+			"network_pause_storm_events_total",
+			"Total pause storm events detected by the NIC (warning = storm detected, error = pause frames suppressed)",
+			[]string{"interface", "level"}, nil,
+		),
+		interfaceInclude: interfaceInclude,
+		interfaceExclude: interfaceExclude,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *PFCCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pfcFramesDesc
+	ch <- c.pfcDurationDesc
+	ch <- c.pfcTransitionsDesc
+	ch <- c.pauseFramesDesc
+	ch <- c.stormEventsDesc
+}
+
+// Collect reads the ethtool statistics of every monitored interface and sends the
+// pause-related ones to the channel. Drivers without such statistics emit no metrics.
+func (c *PFCCollector) Collect(ch chan<- prometheus.Metric) {
+	sock, err := openEthtoolSocket()
+	if err != nil {
+		return
+	}
+	defer sock.Close()
+
+	for _, iface := range listInterfaces(c.interfaceInclude, c.interfaceExclude) {
+		names, values, err := sock.stats(iface)
+		if err != nil {
+			continue
+		}
+
+		for i, name := range names {
+			v := float64(values[i])
+
+			if m := pfcPriorityStat.FindStringSubmatch(name); m != nil {
+				switch m[3] {
+				case "":
+					ch <- prometheus.MustNewConstMetric(c.pfcFramesDesc, prometheus.CounterValue, v, iface, m[1], m[2])
+				case "duration":
+					// mlx5 reports the pause duration in microseconds
+					ch <- prometheus.MustNewConstMetric(c.pfcDurationDesc, prometheus.CounterValue, v/1e6, iface, m[1], m[2])
+				case "transition":
+					ch <- prometheus.MustNewConstMetric(c.pfcTransitionsDesc, prometheus.CounterValue, v, iface, m[1], m[2])
+				}
+			} else if direction, ok := pausePortStats[name]; ok {
+				ch <- prometheus.MustNewConstMetric(c.pauseFramesDesc, prometheus.CounterValue, v, iface, direction)
+			} else if level, ok := pauseStormStats[name]; ok {
+				ch <- prometheus.MustNewConstMetric(c.stormEventsDesc, prometheus.CounterValue, v, iface, level)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package collectors
+
+import (
+	"os"
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Power mode sources: the Tegra nvpmodel configuration and state, and the ACPI
+// platform profile used by firmware on other systems.
+const (
+	nvpmodelConfFile    = "/etc/nvpmodel.conf"
+	nvpmodelStatusFile  = "/var/lib/nvpmodel/status"
+	platformProfileFile = "/sys/firmware/acpi/platform_profile"
+)
+
+var (
+	// < POWER_MODEL ID=0 NAME=MAXN >
+	nvpmodelModelRe = regexp.MustCompile(`(?m)^<\s*POWER_MODEL\s+ID=(\d+)\s+NAME=(\S+)\s*>`)
+	// < PM_CONFIG DEFAULT=2 >
+	nvpmodelDefaultRe = regexp.MustCompile(`(?m)^<\s*PM_CONFIG\s+DEFAULT=(\d+)\s*>`)
+	// pmode:0002 fmode:quiet
+	nvpmodelStatusRe = regexp.MustCompile(`pmode:0*(\d+)`)
+)
+
+// PowerModeCollector exports the selected power/performance mode.
+type PowerModeCollector struct {
+	infoDesc *prometheus.Desc
+}
+
+// NewPowerModeCollector creates a new PowerModeCollector.
+func NewPowerModeCollector() *PowerModeCollector {
+	return &PowerModeCollector{
+		infoDesc: prometheus.NewDesc(
+			"power_mode_info",
+			"Selected power/performance mode, always 1 (source = nvpmodel or platform_profile)",
+			[]string{"source", "mode", "id"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *PowerModeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+}
+
+// Collect reads the nvpmodel mode and the ACPI platform profile and sends them to
+// the channel. Sources that are not present are omitted.
+func (c *PowerModeCollector) Collect(ch chan<- prometheus.Metric) {
+	if id, name, ok := readNvpmodelMode(); ok {
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, "nvpmodel", name, id)
+	}
+	if profile := readSysString(platformProfileFile); profile != "" {
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, "platform_profile", profile, "")
+	}
+}
+
+// readNvpmodelMode returns the ID and name of the active nvpmodel power mode. The
+// status file is only written once a mode has been set; until then the configured
+// default is active.
+func readNvpmodelMode() (id, name string, ok bool) {
+	conf, err := os.ReadFile(nvpmodelConfFile)
+	if err != nil {
+		return "", "", false
+	}
+
+	if status, err := os.ReadFile(nvpmodelStatusFile); err == nil {
+		if m := nvpmodelStatusRe.FindSubmatch(status); m != nil {
+			id = string(m[1])
+		}
+	}
+	if id == "" {
+		m := nvpmodelDefaultRe.FindSubmatch(conf)
+		if m == nil {
+			return "", "", false
+		}
+		id = string(m[1])
+	}
+
+	for _, m := range nvpmodelModelRe.FindAllSubmatch(conf, -1) {
+		if string(m[1]) == id {
+			return id, string(m[2]), true
+		}
+	}
+	return id, "", true
+}
@@ -0,0 +1,165 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ina3221Channels is the number of monitored rails of an INA3221. Its hwmon driver
+// reports bus voltages as in1-in3 and shunt voltages as in4-in6.
+const ina3221Channels = 3
+
+// powerRail is one rail measured by an INA power monitor.
+type powerRail struct {
+	chip, device, rail string
+	volts, amps, watts float64
+}
+
+// PowerRailCollector collects per-rail voltage, current, and power from INA3221
+// and INA2xx power monitors, and the board power derived from them.
+type PowerRailCollector struct {
+	voltageDesc    *prometheus.Desc
+	currentDesc    *prometheus.Desc
+	powerDesc      *prometheus.Desc
+	boardPowerDesc *prometheus.Desc
+
+	totalRails *regexp.Regexp
+}
+
+// NewPowerRailCollector creates a new PowerRailCollector. board_power_watts is the
+// sum of the rails matching totalRails, e.g. the input rail of a board whose monitors
+// also measure its sub-rails; a nil regexp sums all rails.
+func NewPowerRailCollector(totalRails *regexp.Regexp) *PowerRailCollector {
+	labels := []string{"chip", "device", "rail"}
+	return &PowerRailCollector{
+		voltageDesc: prometheus.NewDesc(
+			"power_rail_voltage_volts",
+			"Bus voltage of the power rail in volts",
+			labels, nil,
+		),
+		currentDesc: prometheus.NewDesc(
+			"power_rail_current_amperes",
+			"Current drawn from the power rail in amperes",
+			labels, nil,
+		),
+		powerDesc: prometheus.NewDesc(
+			"power_rail_power_watts",
+			"Power drawn from the power rail in watts (reported by the monitor, or voltage times current)",
+			labels, nil,
+		),
+		boardPowerDesc: prometheus.NewDesc(
+			"board_power_watts",
+			"Total board power in watts: sum of the power rails selected by -power.total-rails",
+			nil, nil,
+		),
+		totalRails: totalRails,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *PowerRailCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.voltageDesc
+	ch <- c.currentDesc
+	ch <- c.powerDesc
+	ch <- c.boardPowerDesc
+}
+
+// Collect reads INA power monitors from /sys/class/hwmon and sends per-rail and
+// board power to the channel. Nothing is sent if the board has no such monitors.
+func (c *PowerRailCollector) Collect(ch chan<- prometheus.Metric) {
+	rails := readPowerRails()
+	if len(rails) == 0 {
+		return
+	}
+
+	var total float64
+	for _, r := range rails {
+		ch <- prometheus.MustNewConstMetric(c.voltageDesc, prometheus.GaugeValue, r.volts, r.chip, r.device, r.rail)
+		ch <- prometheus.MustNewConstMetric(c.currentDesc, prometheus.GaugeValue, r.amps, r.chip, r.device, r.rail)
+		ch <- prometheus.MustNewConstMetric(c.powerDesc, prometheus.GaugeValue, r.watts, r.chip, r.device, r.rail)
+		if c.totalRails == nil || c.totalRails.MatchString(r.rail) {
+			total += r.watts
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(c.boardPowerDesc, prometheus.GaugeValue, total)
+}
+
+// readPowerRails returns the rails of all INA3221 and INA2xx hwmon chips.
+func readPowerRails() []powerRail {
+	chips, err := filepath.Glob("/sys/class/hwmon/hwmon*")
+	if err != nil {
+		return nil
+	}
+
+	var rails []powerRail
+	for _, chip := range chips {
+		name := readSysString(filepath.Join(chip, "name"))
+		device := hwmonDeviceName(chip)
+
+		switch {
+		case name == "ina3221":
+			for ch := 1; ch <= ina3221Channels; ch++ {
+				n := strconv.Itoa(ch)
+				// Disabled channels (no shunt fitted) fail to read
+				mv, ok := readHwmonValue(filepath.Join(chip, "in"+n+"_input"))
+				if !ok {
+					continue
+				}
+				ma, ok := readHwmonValue(filepath.Join(chip, "curr"+n+"_input"))
+				if !ok {
+					continue
+				}
+				rail := readSysString(filepath.Join(chip, "in"+n+"_label"))
+				if rail == "" {
+					rail = "channel" + n
+				}
+				rails = append(rails, powerRail{
+					chip: name, device: device, rail: rail,
+					volts: mv / 1000, amps: ma / 1000, watts: mv * ma / 1e6,
+				})
+			}
+
+		case strings.HasPrefix(name, "ina2"):
+			// INA219/226/230/238/260...: in0 is the shunt and in1 the bus voltage
+			mv, ok := readHwmonValue(filepath.Join(chip, "in1_input"))
+			if !ok {
+				continue
+			}
+			ma, ok := readHwmonValue(filepath.Join(chip, "curr1_input"))
+			if !ok {
+				continue
+			}
+			watts := mv * ma / 1e6
+			if uw, ok := readHwmonValue(filepath.Join(chip, "power1_input")); ok {
+				watts = uw / 1e6
+			}
+			rail := readSysString(filepath.Join(chip, "in1_label"))
+			if rail == "" {
+				rail = device
+			}
+			rails = append(rails, powerRail{
+				chip: name, device: device, rail: rail,
+				volts: mv / 1000, amps: ma / 1000, watts: watts,
+			})
+		}
+	}
+	return rails
+}
+
+// readHwmonValue reads a numeric hwmon attribute.
+func readHwmonValue(path string) (float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,111 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// powerSupplyGauge maps a /sys/class/power_supply/<supply> attribute to an exported gauge.
+type powerSupplyGauge struct {
+	file  string
+	scale float64 // multiplier converting the sysfs unit (µV, µA, µW) to the base unit
+	desc  *prometheus.Desc
+}
+
+// PowerSupplyCollector collects the state of power supplies and AC adapters
+// (including USB-C PD inputs) from /sys/class/power_supply.
+type PowerSupplyCollector struct {
+	gauges    []powerSupplyGauge
+	infoDesc  *prometheus.Desc
+	powerDesc *prometheus.Desc
+}
+
+// NewPowerSupplyCollector creates a new PowerSupplyCollector.
+func NewPowerSupplyCollector() *PowerSupplyCollector {
+	labels := []string{"supply"}
+	gauge := func(file string, scale float64, name, help string) powerSupplyGauge {
+		return powerSupplyGauge{
+			file:  file,
+			scale: scale,
+			desc:  prometheus.NewDesc(name, help, labels, nil),
+		}
+	}
+
+	return &PowerSupplyCollector{
+		gauges: []powerSupplyGauge{
+			gauge("online", 1, "power_supply_online",
+				"Whether the power supply is connected and supplying power (1 = online, 0 = offline)"),
+			gauge("voltage_now", 0.000001, "power_supply_voltage_volts",
+				"Present voltage of the power supply in volts"),
+			gauge("voltage_min", 0.000001, "power_supply_voltage_min_volts",
+				"Minimum voltage of the power supply in volts"),
+			gauge("voltage_max", 0.000001, "power_supply_voltage_max_volts",
+				"Maximum (e.g. negotiated USB PD) voltage of the power supply in volts"),
+			gauge("current_now", 0.000001, "power_supply_current_amperes",
+				"Present current of the power supply in amperes"),
+			gauge("current_max", 0.000001, "power_supply_current_max_amperes",
+				"Maximum (e.g. negotiated USB PD) current of the power supply in amperes"),
+			gauge("input_current_limit", 0.000001, "power_supply_input_current_limit_amperes",
+				"Input current limit of the power supply in amperes"),
+		},
+		infoDesc: prometheus.NewDesc(
+			"power_supply_info",
+			"Type of the power supply, e.g. Mains, USB, Battery, and active USB type, e.g. PD (always 1)",
+			[]string{"supply", "type", "usb_type"}, nil,
+		),
+		powerDesc: prometheus.NewDesc(
+			"power_supply_power_watts",
+			"Present power of the power supply in watts (power_now, or voltage times current if not reported)",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *PowerSupplyCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, g := range c.gauges {
+		ch <- g.desc
+	}
+	ch <- c.infoDesc
+	ch <- c.powerDesc
+}
+
+// Collect reads /sys/class/power_supply and sends the available attributes of each
+// supply to the channel.
+func (c *PowerSupplyCollector) Collect(ch chan<- prometheus.Metric) {
+	entries, err := os.ReadDir("/sys/class/power_supply")
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		supply := e.Name()
+		dir := filepath.Join("/sys/class/power_supply", supply)
+
+		// usb_type lists the supported types with the active one in brackets, in
+		// the same format as a block queue's scheduler attribute
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1,
+			supply, readSysString(filepath.Join(dir, "type")), activeScheduler(readSysString(filepath.Join(dir, "usb_type"))))
+
+		values := make(map[string]float64)
+		for _, g := range c.gauges {
+			v, err := strconv.ParseFloat(readSysString(filepath.Join(dir, g.file)), 64)
+			if err != nil {
+				continue
+			}
+			values[g.file] = v * g.scale
+			ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, values[g.file], supply)
+		}
+
+		if v, err := strconv.ParseFloat(readSysString(filepath.Join(dir, "power_now")), 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.powerDesc, prometheus.GaugeValue, v*0.000001, supply)
+		} else if volts, ok := values["voltage_now"]; ok {
+			if amps, ok := values["current_now"]; ok {
+				ch <- prometheus.MustNewConstMetric(c.powerDesc, prometheus.GaugeValue, volts*amps, supply)
+			}
+		}
+	}
+}
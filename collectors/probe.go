@@ -0,0 +1,251 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ICMP message types used by the echo prober.
+const (
+	icmpv4EchoRequest = 8
+	icmpv4EchoReply   = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+	icmpEchoBytes     = 16 // header (8) + timestamp payload (8)
+)
+
+// icmpSeq numbers the echo requests of all ICMP probes of the exporter, which share
+// the process ID as identifier, so concurrent probes never accept each other's replies.
+var icmpSeq atomic.Uint32
+
+// probeKey identifies one probe target.
+type probeKey struct {
+	module, target string
+}
+
+// probeResult holds the outcome of the most recent round and running totals for a target.
+type probeResult struct {
+	success bool
+	rtt     float64 // seconds, average over replies of the last round
+	sent    float64
+	lost    float64
+}
+
+// ProbeCollector actively probes ICMP and TCP targets at a fixed interval in the
+// background and reports round-trip time and loss.
+type ProbeCollector struct {
+	successDesc *prometheus.Desc
+	rttDesc     *prometheus.Desc
+	sentDesc    *prometheus.Desc
+	lostDesc    *prometheus.Desc
+
+	icmpTargets []string
+	tcpTargets  []string
+	timeout     time.Duration
+	count       int
+
+	mu      sync.Mutex
+	results map[probeKey]*probeResult
+}
+
+// NewProbeCollector creates a new ProbeCollector and starts probing every interval.
+// Each round sends count ICMP echo requests to every host in icmpTargets and opens
+// count TCP connections to every host:port in tcpTargets, waiting at most timeout
+// for each. ICMP probes need a raw socket (root or CAP_NET_RAW).
+func NewProbeCollector(icmpTargets, tcpTargets []string, interval, timeout time.Duration, count int) *ProbeCollector {
+	labels := []string{"module", "target"}
+	c := &ProbeCollector{
+		successDesc: prometheus.NewDesc(
+			"probe_success",
+			"Whether at least one probe to the target succeeded in the last round (1 = success, 0 = failure)",
+			labels, nil,
+		),
+		rttDesc: prometheus.NewDesc(
+			"probe_rtt_seconds",
+			"Average round-trip time (ICMP echo or TCP connect) to the target in the last round in seconds",
+			labels, nil,
+		),
+		sentDesc: prometheus.NewDesc(
+			"probe_packets_sent_total",
+			"Total probes sent to the target",
+			labels, nil,
+		),
+		lostDesc: prometheus.NewDesc(
+			"probe_packets_lost_total",
+			"Total probes to the target without a reply within the timeout",
+			labels, nil,
+		),
+		icmpTargets: icmpTargets,
+		tcpTargets:  tcpTargets,
+		timeout:     timeout,
+		count:       count,
+		results:     make(map[probeKey]*probeResult),
+	}
+
+	go func() {
+		for {
+			c.probeAll()
+			time.Sleep(interval)
+		}
+	}()
+	return c
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ProbeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.successDesc
+	ch <- c.rttDesc
+	ch <- c.sentDesc
+	ch <- c.lostDesc
+}
+
+// Collect sends the latest probe results to the channel. Targets appear once their
+// first round has completed.
+func (c *ProbeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, r := range c.results {
+		success := 0.0
+		if r.success {
+			success = 1
+			ch <- prometheus.MustNewConstMetric(c.rttDesc, prometheus.GaugeValue, r.rtt, key.module, key.target)
+		}
+		ch <- prometheus.MustNewConstMetric(c.successDesc, prometheus.GaugeValue, success, key.module, key.target)
+		ch <- prometheus.MustNewConstMetric(c.sentDesc, prometheus.CounterValue, r.sent, key.module, key.target)
+		ch <- prometheus.MustNewConstMetric(c.lostDesc, prometheus.CounterValue, r.lost, key.module, key.target)
+	}
+}
+
+// probeAll runs one probe round over all targets.
+func (c *ProbeCollector) probeAll() {
+	for _, target := range c.icmpTargets {
+		c.record(probeKey{"icmp", target}, pingICMP(target, c.count, c.timeout))
+	}
+	for _, target := range c.tcpTargets {
+		var rtts []time.Duration
+		for i := 0; i < c.count; i++ {
+			start := time.Now()
+			conn, err := net.DialTimeout("tcp", target, c.timeout)
+			if err != nil {
+				continue
+			}
+			rtts = append(rtts, time.Since(start))
+			conn.Close()
+		}
+		c.record(probeKey{"tcp", target}, rtts)
+	}
+}
+
+// record stores the replies of one round; probes without a reply count as lost.
+func (c *ProbeCollector) record(key probeKey, rtts []time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.results[key]
+	if !ok {
+		r = &probeResult{}
+		c.results[key] = r
+	}
+	r.sent += float64(c.count)
+	r.lost += float64(c.count - len(rtts))
+	r.success = len(rtts) > 0
+	if r.success {
+		var sum time.Duration
+		for _, rtt := range rtts {
+			sum += rtt
+		}
+		r.rtt = (sum / time.Duration(len(rtts))).Seconds()
+	}
+}
+
+// pingICMP sends count echo requests to target, waiting at most timeout for each,
+// and returns the round-trip times of the replies received.
+func pingICMP(target string, count int, timeout time.Duration) []time.Duration {
+	addr, err := net.ResolveIPAddr("ip", target)
+	if err != nil {
+		return nil
+	}
+
+	network, requestType, replyType := "ip4:icmp", byte(icmpv4EchoRequest), byte(icmpv4EchoReply)
+	if addr.IP.To4() == nil {
+		network, requestType, replyType = "ip6:ipv6-icmp", icmpv6EchoRequest, icmpv6EchoReply
+	}
+	conn, err := net.ListenPacket(network, "")
+	if err != nil {
+		log.Printf("icmp probe %s: %v", target, err)
+		return nil
+	}
+	defer conn.Close()
+
+	id := uint16(os.Getpid())
+	var rtts []time.Duration
+	for i := 0; i < count; i++ {
+		seq := uint16(icmpSeq.Add(1))
+		start := time.Now()
+
+		msg := make([]byte, icmpEchoBytes)
+		msg[0] = requestType
+		binary.BigEndian.PutUint16(msg[4:], id)
+		binary.BigEndian.PutUint16(msg[6:], seq)
+		binary.BigEndian.PutUint64(msg[8:], uint64(start.UnixNano()))
+		if requestType == icmpv4EchoRequest {
+			// The kernel computes the ICMPv6 checksum itself
+			binary.BigEndian.PutUint16(msg[2:], internetChecksum(msg))
+		}
+		if _, err := conn.WriteTo(msg, addr); err != nil {
+			continue
+		}
+
+		if err := waitEchoReply(conn, addr.IP, replyType, id, seq, start.Add(timeout)); err == nil {
+			rtts = append(rtts, time.Since(start))
+		}
+	}
+	return rtts
+}
+
+// waitEchoReply reads from conn until the echo reply with the given identifier and
+// sequence number arrives from ip, or the deadline passes. A raw socket receives
+// all ICMP traffic of the host, so unrelated messages are skipped.
+func waitEchoReply(conn net.PacketConn, ip net.IP, replyType byte, id, seq uint16, deadline time.Time) error {
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		src, ok := from.(*net.IPAddr)
+		if !ok || !src.IP.Equal(ip) || n < icmpEchoBytes {
+			continue
+		}
+		if buf[0] == replyType && binary.BigEndian.Uint16(buf[4:]) == id && binary.BigEndian.Uint16(buf[6:]) == seq {
+			return nil
+		}
+	}
+}
+
+// internetChecksum computes the RFC 1071 checksum of b.
+func internetChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
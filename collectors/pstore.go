@@ -0,0 +1,100 @@
+package collectors
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pstoreDirs hold crash records saved by the kernel before a reboot: the pstore
+// filesystem itself, and the archive systemd-pstore moves records to at boot.
+var pstoreDirs = []string{"/sys/fs/pstore", "/var/lib/systemd/pstore"}
+
+// PstoreCollector exports crash records (kernel panics and oopses) that the pstore
+// backends (EFI variables, ramoops, ERST) preserved from previous boots.
+type PstoreCollector struct {
+	recordsDesc     *prometheus.Desc
+	latestCrashDesc *prometheus.Desc
+
+	reasons     map[string]float64
+	latestCrash time.Time
+}
+
+// NewPstoreCollector creates a new PstoreCollector and scans the crash records.
+// Records are only written before a reboot, so they are scanned once at startup.
+func NewPstoreCollector() *PstoreCollector {
+	c := &PstoreCollector{
+		recordsDesc: prometheus.NewDesc(
+			"pstore_crash_records",
+			"Number of crash records from previous boots found in pstore (reason = panic, oops, emergency, ...)",
+			[]string{"reason"}, nil,
+		),
+		latestCrashDesc: prometheus.NewDesc(
+			"pstore_latest_crash_timestamp_seconds",
+			"Unix timestamp of the most recent crash record in pstore",
+			nil, nil,
+		),
+		reasons: map[string]float64{"panic": 0, "oops": 0},
+	}
+
+	for _, dir := range pstoreDirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasPrefix(d.Name(), "dmesg-") {
+				return nil
+			}
+			reason, ok := readPstoreReason(path)
+			if !ok {
+				return nil
+			}
+			c.reasons[reason]++
+			// pstore sets the file time to the time the record was written
+			if info, err := d.Info(); err == nil && info.ModTime().After(c.latestCrash) {
+				c.latestCrash = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return c
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *PstoreCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.recordsDesc
+	ch <- c.latestCrashDesc
+}
+
+// Collect sends the crash record counts found at startup to the channel.
+func (c *PstoreCollector) Collect(ch chan<- prometheus.Metric) {
+	for reason, n := range c.reasons {
+		ch <- prometheus.MustNewConstMetric(c.recordsDesc, prometheus.GaugeValue, n, reason)
+	}
+	if !c.latestCrash.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.latestCrashDesc, prometheus.GaugeValue, float64(c.latestCrash.Unix()))
+	}
+}
+
+// readPstoreReason returns the lower-cased dump reason of a pstore dmesg record
+// from its first line, e.g. "Panic#1 Part1". Large logs are split over several
+// records; only the first part is counted so each crash counts once.
+func readPstoreReason(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return "", false
+	}
+	reason, part, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "#")
+	if !ok || !strings.HasSuffix(part, " Part1") {
+		return "", false
+	}
+	return strings.ToLower(reason), true
+}
@@ -0,0 +1,281 @@
+package collectors
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// redfishChassis is the subset of a Redfish Chassis resource used by the collector.
+type redfishChassis struct {
+	ID      string `json:"Id"`
+	Thermal struct {
+		ODataID string `json:"@odata.id"`
+	} `json:"Thermal"`
+	Power struct {
+		ODataID string `json:"@odata.id"`
+	} `json:"Power"`
+}
+
+// redfishStatus is the common Status object of Redfish resources.
+type redfishStatus struct {
+	State  string `json:"State"`
+	Health string `json:"Health"`
+}
+
+// redfishThermal is the subset of a Redfish Thermal resource used by the collector.
+type redfishThermal struct {
+	Temperatures []struct {
+		Name           string        `json:"Name"`
+		ReadingCelsius *float64      `json:"ReadingCelsius"`
+		Status         redfishStatus `json:"Status"`
+	} `json:"Temperatures"`
+	Fans []struct {
+		Name         string        `json:"Name"`
+		FanName      string        `json:"FanName"` // Redfish 2016 schema
+		Reading      *float64      `json:"Reading"`
+		ReadingUnits string        `json:"ReadingUnits"`
+		Status       redfishStatus `json:"Status"`
+	} `json:"Fans"`
+}
+
+// redfishPower is the subset of a Redfish Power resource used by the collector.
+type redfishPower struct {
+	PowerControl []struct {
+		PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+	} `json:"PowerControl"`
+	PowerSupplies []struct {
+		Name                 string        `json:"Name"`
+		PowerInputWatts      *float64      `json:"PowerInputWatts"`
+		LastPowerOutputWatts *float64      `json:"LastPowerOutputWatts"`
+		Status               redfishStatus `json:"Status"`
+	} `json:"PowerSupplies"`
+}
+
+// RedfishCollector collects temperature, fan, and power supply sensors from a
+// baseboard management controller (BMC) through its Redfish API.
+type RedfishCollector struct {
+	upDesc            *prometheus.Desc
+	temperatureDesc   *prometheus.Desc
+	fanRPMDesc        *prometheus.Desc
+	fanPercentDesc    *prometheus.Desc
+	powerConsumedDesc *prometheus.Desc
+	psuInputDesc      *prometheus.Desc
+	psuOutputDesc     *prometheus.Desc
+	healthDesc        *prometheus.Desc
+
+	baseURL      string
+	username     string
+	passwordFile string
+	client       *http.Client
+}
+
+// NewRedfishCollector creates a new RedfishCollector querying the BMC at baseURL
+// (e.g. https://169.254.0.1), authenticating with HTTP basic auth if username is
+// set. The password is read from passwordFile on every scrape so it can be rotated.
+// The BMC certificate is not verified unless verifyTLS is set, as it is usually
+// self-signed.
+func NewRedfishCollector(baseURL, username, passwordFile string, verifyTLS bool, timeout time.Duration) *RedfishCollector {
+	return &RedfishCollector{
+		upDesc: prometheus.NewDesc(
+			"bmc_up",
+			"Whether the BMC Redfish API could be queried (1 = success)",
+			nil, nil,
+		),
+		temperatureDesc: prometheus.NewDesc(
+			"bmc_temperature_celsius",
+			"Temperature reported by the BMC in degrees Celsius",
+			[]string{"chassis", "sensor"}, nil,
+		),
+		fanRPMDesc: prometheus.NewDesc(
+			"bmc_fan_speed_rpm",
+			"Fan speed reported by the BMC in RPM",
+			[]string{"chassis", "fan"}, nil,
+		),
+		fanPercentDesc: prometheus.NewDesc(
+			"bmc_fan_speed_percent",
+			"Fan speed reported by the BMC as a percentage of the maximum (0-100)",
+			[]string{"chassis", "fan"}, nil,
+		),
+		powerConsumedDesc: prometheus.NewDesc(
+			"bmc_power_consumed_watts",
+			"Power consumed by the chassis as measured by the BMC in Watts",
+			[]string{"chassis"}, nil,
+		),
+		psuInputDesc: prometheus.NewDesc(
+			"bmc_psu_input_watts",
+			"Input power of the power supply in Watts",
+			[]string{"chassis", "psu"}, nil,
+		),
+		psuOutputDesc: prometheus.NewDesc(
+			"bmc_psu_output_watts",
+			"Output power of the power supply in Watts",
+			[]string{"chassis", "psu"}, nil,
+		),
+		healthDesc: prometheus.NewDesc(
+			"bmc_sensor_health",
+			"Health of the sensor or component as reported by the BMC (0 = OK, 1 = Warning, 2 = Critical)",
+			[]string{"chassis", "type", "name"}, nil,
+		),
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		username:     username,
+		passwordFile: passwordFile,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: !verifyTLS},
+			},
+		},
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *RedfishCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upDesc
+	ch <- c.temperatureDesc
+	ch <- c.fanRPMDesc
+	ch <- c.fanPercentDesc
+	ch <- c.powerConsumedDesc
+	ch <- c.psuInputDesc
+	ch <- c.psuOutputDesc
+	ch <- c.healthDesc
+}
+
+// Collect walks the Redfish chassis collection and sends the thermal and power
+// sensors of every chassis to the channel. Absent or disabled sensors are skipped.
+func (c *RedfishCollector) Collect(ch chan<- prometheus.Metric) {
+	var collection struct {
+		Members []struct {
+			ODataID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+	if err := c.get("/redfish/v1/Chassis", &collection); err != nil {
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 0)
+		ch <- prometheus.NewInvalidMetric(c.upDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 1)
+
+	for _, member := range collection.Members {
+		var chassis redfishChassis
+		if err := c.get(member.ODataID, &chassis); err != nil {
+			ch <- prometheus.NewInvalidMetric(c.upDesc, err)
+			continue
+		}
+
+		if chassis.Thermal.ODataID != "" {
+			var thermal redfishThermal
+			if err := c.get(chassis.Thermal.ODataID, &thermal); err != nil {
+				ch <- prometheus.NewInvalidMetric(c.temperatureDesc, err)
+			} else {
+				c.collectThermal(ch, chassis.ID, &thermal)
+			}
+		}
+		if chassis.Power.ODataID != "" {
+			var power redfishPower
+			if err := c.get(chassis.Power.ODataID, &power); err != nil {
+				ch <- prometheus.NewInvalidMetric(c.powerConsumedDesc, err)
+			} else {
+				c.collectPower(ch, chassis.ID, &power)
+			}
+		}
+	}
+}
+
+// collectThermal sends the temperature and fan readings of one chassis.
+func (c *RedfishCollector) collectThermal(ch chan<- prometheus.Metric, chassis string, thermal *redfishThermal) {
+	for _, t := range thermal.Temperatures {
+		if t.ReadingCelsius == nil || t.Status.State == "Absent" {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.temperatureDesc, prometheus.GaugeValue, *t.ReadingCelsius, chassis, t.Name)
+		c.sendHealth(ch, chassis, "temperature", t.Name, t.Status)
+	}
+	for _, f := range thermal.Fans {
+		if f.Reading == nil || f.Status.State == "Absent" {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = f.FanName
+		}
+		desc := c.fanRPMDesc
+		if f.ReadingUnits == "Percent" {
+			desc = c.fanPercentDesc
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *f.Reading, chassis, name)
+		c.sendHealth(ch, chassis, "fan", name, f.Status)
+	}
+}
+
+// collectPower sends the power consumption and power supply readings of one chassis.
+func (c *RedfishCollector) collectPower(ch chan<- prometheus.Metric, chassis string, power *redfishPower) {
+	// Only the first PowerControl entry covers the whole chassis
+	if len(power.PowerControl) > 0 && power.PowerControl[0].PowerConsumedWatts != nil {
+		ch <- prometheus.MustNewConstMetric(c.powerConsumedDesc, prometheus.GaugeValue, *power.PowerControl[0].PowerConsumedWatts, chassis)
+	}
+	for _, psu := range power.PowerSupplies {
+		if psu.Status.State == "Absent" {
+			continue
+		}
+		if psu.PowerInputWatts != nil {
+			ch <- prometheus.MustNewConstMetric(c.psuInputDesc, prometheus.GaugeValue, *psu.PowerInputWatts, chassis, psu.Name)
+		}
+		if psu.LastPowerOutputWatts != nil {
+			ch <- prometheus.MustNewConstMetric(c.psuOutputDesc, prometheus.GaugeValue, *psu.LastPowerOutputWatts, chassis, psu.Name)
+		}
+		c.sendHealth(ch, chassis, "psu", psu.Name, psu.Status)
+	}
+}
+
+// sendHealth sends the health of a sensor if the BMC reports one.
+func (c *RedfishCollector) sendHealth(ch chan<- prometheus.Metric, chassis, typ, name string, status redfishStatus) {
+	var v float64
+	switch status.Health {
+	case "OK":
+		v = 0
+	case "Warning":
+		v = 1
+	case "Critical":
+		v = 2
+	default:
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.healthDesc, prometheus.GaugeValue, v, chassis, typ, name)
+}
+
+// get fetches a Redfish resource and decodes it into v.
+func (c *RedfishCollector) get(path string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.username != "" {
+		password := ""
+		if c.passwordFile != "" {
+			data, err := os.ReadFile(c.passwordFile)
+			if err != nil {
+				return err
+			}
+			password = strings.TrimSpace(string(data))
+		}
+		req.SetBasicAuth(c.username, password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
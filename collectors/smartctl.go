@@ -0,0 +1,202 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// smartctlScan is the subset of `smartctl --scan-open --json` output used by the collector.
+type smartctlScan struct {
+	Devices []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"devices"`
+}
+
+// smartctlDevice is the subset of `smartctl --json --all` output used by the collector.
+type smartctlDevice struct {
+	ModelName       string `json:"model_name"`
+	SerialNumber    string `json:"serial_number"`
+	FirmwareVersion string `json:"firmware_version"`
+	SmartStatus     *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours float64 `json:"hours"`
+	} `json:"power_on_time"`
+	PowerCycleCount    *float64 `json:"power_cycle_count"`
+	ATASmartAttributes *struct {
+		Table []struct {
+			ID     int     `json:"id"`
+			Name   string  `json:"name"`
+			Value  float64 `json:"value"`
+			Worst  float64 `json:"worst"`
+			Thresh float64 `json:"thresh"`
+			Raw    struct {
+				Value float64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+// SmartctlCollector collects SMART attributes by running smartctl with JSON output.
+type SmartctlCollector struct {
+	smartctlPath string
+
+	infoDesc        *prometheus.Desc
+	passedDesc      *prometheus.Desc
+	tempDesc        *prometheus.Desc
+	powerOnDesc     *prometheus.Desc
+	powerCyclesDesc *prometheus.Desc
+	attrValueDesc   *prometheus.Desc
+	attrWorstDesc   *prometheus.Desc
+	attrThreshDesc  *prometheus.Desc
+	attrRawDesc     *prometheus.Desc
+}
+
+// NewSmartctlCollector creates a new SmartctlCollector that runs the smartctl binary at smartctlPath.
+func NewSmartctlCollector(smartctlPath string) *SmartctlCollector {
+	labels := []string{"device"}
+	attrLabels := []string{"device", "attribute_id", "attribute_name"}
+	return &SmartctlCollector{
+		smartctlPath: smartctlPath,
+		infoDesc: prometheus.NewDesc(
+			"smartctl_device_info",
+			"SMART device information (always 1)",
+			[]string{"device", "type", "model", "serial", "firmware"}, nil,
+		),
+		passedDesc: prometheus.NewDesc(
+			"smartctl_device_smart_passed",
+			"Whether the device passed its SMART overall health self-assessment (1 = passed, 0 = failed)",
+			labels, nil,
+		),
+		tempDesc: prometheus.NewDesc(
+			"smartctl_device_temperature_celsius",
+			"Current device temperature reported by SMART in degrees Celsius",
+			labels, nil,
+		),
+		powerOnDesc: prometheus.NewDesc(
+			"smartctl_device_power_on_hours_total",
+			"Total device power-on hours reported by SMART",
+			labels, nil,
+		),
+		powerCyclesDesc: prometheus.NewDesc(
+			"smartctl_device_power_cycles_total",
+			"Total device power cycles reported by SMART",
+			labels, nil,
+		),
+		attrValueDesc: prometheus.NewDesc(
+			"smartctl_device_attribute_value",
+			"Normalized current value of an ATA SMART attribute",
+			attrLabels, nil,
+		),
+		attrWorstDesc: prometheus.NewDesc(
+			"smartctl_device_attribute_worst",
+			"Normalized worst recorded value of an ATA SMART attribute",
+			attrLabels, nil,
+		),
+		attrThreshDesc: prometheus.NewDesc(
+			"smartctl_device_attribute_threshold",
+			"Normalized failure threshold of an ATA SMART attribute",
+			attrLabels, nil,
+		),
+		attrRawDesc: prometheus.NewDesc(
+			"smartctl_device_attribute_raw",
+			"Raw value of an ATA SMART attribute",
+			attrLabels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *SmartctlCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.passedDesc
+	ch <- c.tempDesc
+	ch <- c.powerOnDesc
+	ch <- c.powerCyclesDesc
+	ch <- c.attrValueDesc
+	ch <- c.attrWorstDesc
+	ch <- c.attrThreshDesc
+	ch <- c.attrRawDesc
+}
+
+// Collect scans for SMART-capable devices and sends their attributes to the channel.
+// If smartctl is not available or fails, no metrics are emitted.
+func (c *SmartctlCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+// CollectContext is Collect, killing smartctl when ctx is done.
+func (c *SmartctlCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var scan smartctlScan
+	if err := c.runJSON(ctx, &scan, "--scan-open", "--json"); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.infoDesc, fmt.Errorf("smartctl scan failed: %w", err))
+		return
+	}
+
+	for _, d := range scan.Devices {
+		var dev smartctlDevice
+		if err := c.runJSON(ctx, &dev, "--json", "--all", "--device", d.Type, d.Name); err != nil {
+			ch <- prometheus.NewInvalidMetric(c.infoDesc, fmt.Errorf("smartctl %s failed: %w", d.Name, err))
+			continue
+		}
+		c.collectDevice(ch, filepath.Base(d.Name), d.Type, &dev)
+	}
+}
+
+// collectDevice sends the metrics of a single smartctl device report to the channel.
+func (c *SmartctlCollector) collectDevice(ch chan<- prometheus.Metric, device, devType string, dev *smartctlDevice) {
+	ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1,
+		device, devType, dev.ModelName, dev.SerialNumber, dev.FirmwareVersion)
+
+	if dev.SmartStatus != nil {
+		passed := 0.0
+		if dev.SmartStatus.Passed {
+			passed = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.passedDesc, prometheus.GaugeValue, passed, device)
+	}
+	if dev.Temperature != nil {
+		ch <- prometheus.MustNewConstMetric(c.tempDesc, prometheus.GaugeValue, dev.Temperature.Current, device)
+	}
+	if dev.PowerOnTime != nil {
+		ch <- prometheus.MustNewConstMetric(c.powerOnDesc, prometheus.CounterValue, dev.PowerOnTime.Hours, device)
+	}
+	if dev.PowerCycleCount != nil {
+		ch <- prometheus.MustNewConstMetric(c.powerCyclesDesc, prometheus.CounterValue, *dev.PowerCycleCount, device)
+	}
+
+	if dev.ATASmartAttributes == nil {
+		return
+	}
+	for _, attr := range dev.ATASmartAttributes.Table {
+		labels := []string{device, strconv.Itoa(attr.ID), attr.Name}
+		ch <- prometheus.MustNewConstMetric(c.attrValueDesc, prometheus.GaugeValue, attr.Value, labels...)
+		ch <- prometheus.MustNewConstMetric(c.attrWorstDesc, prometheus.GaugeValue, attr.Worst, labels...)
+		ch <- prometheus.MustNewConstMetric(c.attrThreshDesc, prometheus.GaugeValue, attr.Thresh, labels...)
+		ch <- prometheus.MustNewConstMetric(c.attrRawDesc, prometheus.GaugeValue, attr.Raw.Value, labels...)
+	}
+}
+
+// runJSON runs smartctl with the given arguments and decodes its JSON output into v.
+// smartctl uses a non-zero exit status bit mask to report device conditions, so the
+// output is decoded even when the command exits with an error.
+func (c *SmartctlCollector) runJSON(ctx context.Context, v any, args ...string) error {
+	out, err := exec.CommandContext(ctx, c.smartctlPath, args...).Output()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && len(out) > 0) {
+		return err
+	}
+	return json.Unmarshal(out, v)
+}
@@ -0,0 +1,136 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// sockstatField maps a protocol/key pair from /proc/net/sockstat{,6} to an exported gauge.
+// Values counted in pages are converted to bytes.
+type sockstatField struct {
+	protocol string
+	key      string
+	pages    bool
+	desc     *prometheus.Desc
+}
+
+// SockstatCollector collects socket usage from /proc/net/sockstat and /proc/net/sockstat6.
+type SockstatCollector struct {
+	fields          []sockstatField
+	tcpMemLimitDesc *prometheus.Desc
+	pageSize        float64
+}
+
+// NewSockstatCollector creates a new SockstatCollector.
+func NewSockstatCollector() *SockstatCollector {
+	field := func(protocol, key string, pages bool, name, help string) sockstatField {
+		return sockstatField{protocol, key, pages, prometheus.NewDesc(name, help, nil, nil)}
+	}
+
+	return &SockstatCollector{
+		fields: []sockstatField{
+			field("sockets", "used", false, "sockstat_sockets_used",
+				"Number of sockets in use across all protocols"),
+			field("TCP", "inuse", false, "sockstat_tcp_inuse",
+				"Number of IPv4 TCP sockets in use"),
+			field("TCP", "orphan", false, "sockstat_tcp_orphan",
+				"Number of orphaned TCP sockets not attached to any file descriptor"),
+			field("TCP", "tw", false, "sockstat_tcp_timewait",
+				"Number of TCP sockets in TIME_WAIT state"),
+			field("TCP", "alloc", false, "sockstat_tcp_alloc",
+				"Number of allocated TCP sockets (IPv4 and IPv6)"),
+			field("TCP", "mem", true, "sockstat_tcp_mem_bytes",
+				"Memory used by TCP socket buffers in bytes"),
+			field("UDP", "inuse", false, "sockstat_udp_inuse",
+				"Number of IPv4 UDP sockets in use"),
+			field("UDP", "mem", true, "sockstat_udp_mem_bytes",
+				"Memory used by UDP socket buffers in bytes"),
+			field("RAW", "inuse", false, "sockstat_raw_inuse",
+				"Number of IPv4 raw sockets in use"),
+			field("FRAG", "memory", false, "sockstat_frag_memory_bytes",
+				"Memory used by IPv4 fragment reassembly in bytes"),
+			field("TCP6", "inuse", false, "sockstat_tcp6_inuse",
+				"Number of IPv6 TCP sockets in use"),
+			field("UDP6", "inuse", false, "sockstat_udp6_inuse",
+				"Number of IPv6 UDP sockets in use"),
+		},
+		tcpMemLimitDesc: prometheus.NewDesc(
+			"sockstat_tcp_mem_limit_bytes",
+			"TCP socket buffer memory limit above which allocations fail (third value of net.ipv4.tcp_mem) in bytes",
+			nil, nil,
+		),
+		pageSize: float64(os.Getpagesize()),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *SockstatCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, f := range c.fields {
+		ch <- f.desc
+	}
+	ch <- c.tcpMemLimitDesc
+}
+
+// Collect reads socket statistics and sends them to the channel.
+func (c *SockstatCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := make(map[string]map[string]float64)
+	for _, path := range []string{"/proc/net/sockstat", "/proc/net/sockstat6"} {
+		if err := readSockstat(path, stats); err != nil {
+			continue
+		}
+	}
+
+	for _, f := range c.fields {
+		v, ok := stats[f.protocol][f.key]
+		if !ok {
+			continue
+		}
+		if f.pages {
+			v *= c.pageSize
+		}
+		ch <- prometheus.MustNewConstMetric(f.desc, prometheus.GaugeValue, v)
+	}
+
+	// net.ipv4.tcp_mem: "min pressure max" in pages
+	if fields := strings.Fields(readSysString("/proc/sys/net/ipv4/tcp_mem")); len(fields) == 3 {
+		if max, err := strconv.ParseFloat(fields[2], 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.tcpMemLimitDesc, prometheus.GaugeValue, max*c.pageSize)
+		}
+	}
+}
+
+// readSockstat parses lines such as "TCP: inuse 5 orphan 0 tw 2 alloc 8 mem 1"
+// into stats (protocol -> key -> value).
+func readSockstat(path string, stats map[string]map[string]float64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		protocol, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if stats[protocol] == nil {
+			stats[protocol] = make(map[string]float64)
+		}
+		for i := 0; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				continue
+			}
+			stats[protocol][fields[i]] = v
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,121 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Column indexes of /proc/net/softnet_stat.
+const (
+	softnetProcessed      = 0
+	softnetDropped        = 1
+	softnetTimeSqueeze    = 2
+	softnetReceivedRPS    = 9
+	softnetFlowLimitCount = 10
+	softnetBacklogLen     = 11 // kernel 5.10+
+	softnetCPUIndex       = 12 // kernel 5.10+
+)
+
+// SoftnetCollector collects per-CPU packet processing statistics of the kernel
+// network stack from /proc/net/softnet_stat.
+type SoftnetCollector struct {
+	processedDesc   *prometheus.Desc
+	droppedDesc     *prometheus.Desc
+	timeSqueezeDesc *prometheus.Desc
+	receivedRPSDesc *prometheus.Desc
+	flowLimitDesc   *prometheus.Desc
+	backlogLenDesc  *prometheus.Desc
+}
+
+// NewSoftnetCollector creates a new SoftnetCollector.
+func NewSoftnetCollector() *SoftnetCollector {
+	labels := []string{"cpu"}
+	return &SoftnetCollector{
+		processedDesc: prometheus.NewDesc(
+			"softnet_processed_total",
+			"Total packets processed by the CPU's network receive softirq",
+			labels, nil,
+		),
+		droppedDesc: prometheus.NewDesc(
+			"softnet_dropped_total",
+			"Total packets dropped because the CPU's input backlog queue was full (net.core.netdev_max_backlog)",
+			labels, nil,
+		),
+		timeSqueezeDesc: prometheus.NewDesc(
+			"softnet_times_squeezed_total",
+			"Total times the receive softirq ran out of budget with work remaining (net.core.netdev_budget)",
+			labels, nil,
+		),
+		receivedRPSDesc: prometheus.NewDesc(
+			"softnet_received_rps_total",
+			"Total times the CPU was woken up by an inter-processor interrupt to process packets (RPS)",
+			labels, nil,
+		),
+		flowLimitDesc: prometheus.NewDesc(
+			"softnet_flow_limit_count_total",
+			"Total times the flow limit was reached",
+			labels, nil,
+		),
+		backlogLenDesc: prometheus.NewDesc(
+			"softnet_backlog_length",
+			"Current length of the CPU's input backlog queue",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *SoftnetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.processedDesc
+	ch <- c.droppedDesc
+	ch <- c.timeSqueezeDesc
+	ch <- c.receivedRPSDesc
+	ch <- c.flowLimitDesc
+	ch <- c.backlogLenDesc
+}
+
+// Collect reads /proc/net/softnet_stat and sends per-CPU statistics to the channel.
+func (c *SoftnetCollector) Collect(ch chan<- prometheus.Metric) {
+	f, err := os.Open("/proc/net/softnet_stat")
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 0; scanner.Scan(); line++ {
+		// One line of hexadecimal columns per online CPU
+		fields := strings.Fields(scanner.Text())
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			v, err := strconv.ParseUint(field, 16, 32)
+			if err != nil {
+				continue
+			}
+			values[i] = float64(v)
+		}
+		if len(values) <= softnetFlowLimitCount {
+			continue
+		}
+
+		// Lines of offline CPUs are skipped, so the line number only matches the
+		// CPU number on kernels that do not report it
+		cpu := strconv.Itoa(line)
+		if len(values) > softnetCPUIndex {
+			cpu = strconv.Itoa(int(values[softnetCPUIndex]))
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.processedDesc, prometheus.CounterValue, values[softnetProcessed], cpu)
+		ch <- prometheus.MustNewConstMetric(c.droppedDesc, prometheus.CounterValue, values[softnetDropped], cpu)
+		ch <- prometheus.MustNewConstMetric(c.timeSqueezeDesc, prometheus.CounterValue, values[softnetTimeSqueeze], cpu)
+		ch <- prometheus.MustNewConstMetric(c.receivedRPSDesc, prometheus.CounterValue, values[softnetReceivedRPS], cpu)
+		ch <- prometheus.MustNewConstMetric(c.flowLimitDesc, prometheus.CounterValue, values[softnetFlowLimitCount], cpu)
+		if len(values) > softnetBacklogLen {
+			ch <- prometheus.MustNewConstMetric(c.backlogLenDesc, prometheus.GaugeValue, values[softnetBacklogLen], cpu)
+		}
+	}
+}
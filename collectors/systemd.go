@@ -0,0 +1,181 @@
+package collectors
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultSystemdUnits are the units watched by the systemd collector by default.
+const DefaultSystemdUnits = "docker.service,nvidia-persistenced.service,nvidia-fabricmanager.service,sshd.service"
+
+// systemdTimeout bounds the D-Bus exchange of a single scrape.
+const systemdTimeout = 5 * time.Second
+
+// systemdActiveStates are the ActiveState values of a systemd unit.
+var systemdActiveStates = []string{"active", "activating", "deactivating", "inactive", "failed", "reloading"}
+
+// SystemdCollector collects the state of a configured set of systemd units
+// through the systemd D-Bus API.
+type SystemdCollector struct {
+	stateDesc      *prometheus.Desc
+	loadedDesc     *prometheus.Desc
+	failedDesc     *prometheus.Desc
+	failedUnitDesc *prometheus.Desc
+
+	units          []string
+	failedUnitInfo bool
+}
+
+// NewSystemdCollector creates a new SystemdCollector watching units. Names without
+// a unit type suffix are treated as services. If failedUnitInfo is set, every
+// failed unit is also reported by name.
+func NewSystemdCollector(units []string, failedUnitInfo bool) *SystemdCollector {
+	names := make([]string, 0, len(units))
+	for _, unit := range units {
+		if !strings.Contains(unit, ".") {
+			unit += ".service"
+		}
+		names = append(names, unit)
+	}
+	return &SystemdCollector{
+		stateDesc: prometheus.NewDesc(
+			"systemd_unit_state",
+			"Whether the systemd unit is in the given active state (1 = current state)",
+			[]string{"unit", "state"}, nil,
+		),
+		loadedDesc: prometheus.NewDesc(
+			"systemd_unit_loaded",
+			"Whether the systemd unit file was found and loaded (0 = not found, masked, or invalid)",
+			[]string{"unit"}, nil,
+		),
+		failedDesc: prometheus.NewDesc(
+			"systemd_failed_units",
+			"Number of systemd units in the failed state",
+			nil, nil,
+		),
+		failedUnitDesc: prometheus.NewDesc(
+			"systemd_failed_unit_info",
+			"Systemd unit in the failed state, always 1",
+			[]string{"unit"}, nil,
+		),
+		units:          names,
+		failedUnitInfo: failedUnitInfo,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *SystemdCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.stateDesc
+	ch <- c.loadedDesc
+	ch <- c.failedDesc
+	ch <- c.failedUnitDesc
+}
+
+// Collect queries the failed units and the load and active state of each watched
+// unit over the system bus and sends them to the channel. If the bus is
+// unreachable, no metrics are emitted.
+func (c *SystemdCollector) Collect(ch chan<- prometheus.Metric) {
+	conn, err := dialSystemBus(time.Now().Add(systemdTimeout))
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.stateDesc, fmt.Errorf("cannot connect to the system bus: %w", err))
+		return
+	}
+	defer conn.Close()
+
+	if failed, err := listFailedSystemdUnits(conn); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.failedDesc, fmt.Errorf("listing failed units: %w", err))
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.failedDesc, prometheus.GaugeValue, float64(len(failed)))
+		if c.failedUnitInfo {
+			for _, unit := range failed {
+				ch <- prometheus.MustNewConstMetric(c.failedUnitDesc, prometheus.GaugeValue, 1, unit)
+			}
+		}
+	}
+
+	for _, unit := range c.units {
+		loadState, activeState, err := readSystemdUnitState(conn, unit)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(c.stateDesc, fmt.Errorf("%s: %w", unit, err))
+			continue
+		}
+
+		loaded := 0.0
+		if loadState == "loaded" {
+			loaded = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.loadedDesc, prometheus.GaugeValue, loaded, unit)
+
+		for _, state := range systemdActiveStates {
+			v := 0.0
+			if state == activeState {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.stateDesc, prometheus.GaugeValue, v, unit, state)
+		}
+	}
+}
+
+// readSystemdUnitState returns the LoadState and ActiveState properties of a unit.
+// LoadUnit is used instead of GetUnit so units that are not currently loaded, such
+// as stopped services, still resolve.
+func readSystemdUnitState(conn *dbusConn, unit string) (loadState, activeState string, err error) {
+	const dest = "org.freedesktop.systemd1"
+
+	reply, err := conn.call(dest, "/org/freedesktop/systemd1", "org.freedesktop.systemd1.Manager", "LoadUnit", unit)
+	if err != nil {
+		return "", "", err
+	}
+	if reply.signature != "o" {
+		return "", "", fmt.Errorf("unexpected LoadUnit reply signature %q", reply.signature)
+	}
+	d := reply.decoder()
+	path := d.string()
+	if d.err != nil {
+		return "", "", d.err
+	}
+
+	if loadState, err = conn.getStringProperty(dest, path, "org.freedesktop.systemd1.Unit", "LoadState"); err != nil {
+		return "", "", err
+	}
+	if activeState, err = conn.getStringProperty(dest, path, "org.freedesktop.systemd1.Unit", "ActiveState"); err != nil {
+		return "", "", err
+	}
+	return loadState, activeState, nil
+}
+
+// listFailedSystemdUnits returns the names of all units in the failed state.
+func listFailedSystemdUnits(conn *dbusConn) ([]string, error) {
+	reply, err := conn.call("org.freedesktop.systemd1", "/org/freedesktop/systemd1", "org.freedesktop.systemd1.Manager",
+		"ListUnitsFiltered", []string{"failed"})
+	if err != nil {
+		return nil, err
+	}
+	// (name, description, load state, active state, sub state, followed, unit path,
+	// job ID, job type, job path)
+	if reply.signature != "a(ssssssouso)" {
+		return nil, fmt.Errorf("unexpected ListUnitsFiltered reply signature %q", reply.signature)
+	}
+
+	d := reply.decoder()
+	size := int(d.uint32())
+	d.align(8) // struct elements; padding is not included in size
+	end := d.pos + size
+
+	var units []string
+	for d.err == nil && d.pos < end {
+		d.align(8)
+		units = append(units, d.string())
+		for i := 0; i < 5; i++ {
+			d.string()
+		}
+		d.string() // unit path
+		d.uint32() // job ID
+		d.string() // job type
+		d.string() // job path
+	}
+	return units, d.err
+}
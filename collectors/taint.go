@@ -0,0 +1,77 @@
+package collectors
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// kernelTaintFlags are the kernel taint flags by bit number, as documented in
+// Documentation/admin-guide/tainted-kernels.rst.
+var kernelTaintFlags = []string{
+	"proprietary_module",    // P
+	"forced_module",         // F
+	"cpu_out_of_spec",       // S
+	"forced_rmmod",          // R
+	"machine_check",         // M
+	"bad_page",              // B
+	"user",                  // U
+	"die",                   // D
+	"overridden_acpi_table", // A
+	"warn",                  // W
+	"staging_driver",        // C
+	"firmware_workaround",   // I
+	"oot_module",            // O
+	"unsigned_module",       // E
+	"soft_lockup",           // L
+	"livepatch",             // K
+	"aux",                   // X
+	"randstruct",            // T
+	"test",                  // N
+}
+
+// KernelTaintCollector collects the kernel taint state.
+type KernelTaintCollector struct {
+	taintedDesc *prometheus.Desc
+	flagDesc    *prometheus.Desc
+}
+
+// NewKernelTaintCollector creates a new KernelTaintCollector.
+func NewKernelTaintCollector() *KernelTaintCollector {
+	return &KernelTaintCollector{
+		taintedDesc: prometheus.NewDesc(
+			"node_kernel_tainted",
+			"Raw kernel taint bitmask from /proc/sys/kernel/tainted (0 = not tainted)",
+			nil, nil,
+		),
+		flagDesc: prometheus.NewDesc(
+			"node_kernel_taint",
+			"Whether the kernel taint flag is set (1 = set)",
+			[]string{"flag"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *KernelTaintCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.taintedDesc
+	ch <- c.flagDesc
+}
+
+// Collect reads /proc/sys/kernel/tainted and sends the bitmask and every flag to
+// the channel.
+func (c *KernelTaintCollector) Collect(ch chan<- prometheus.Metric) {
+	tainted, err := strconv.ParseUint(readSysString("/proc/sys/kernel/tainted"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.taintedDesc, prometheus.GaugeValue, float64(tainted))
+	for bit, flag := range kernelTaintFlags {
+		v := 0.0
+		if tainted&(1<<bit) != 0 {
+			v = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.flagDesc, prometheus.GaugeValue, v, flag)
+	}
+}
@@ -0,0 +1,93 @@
+package collectors
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// thermalZone is one /sys/class/thermal/thermal_zone<N> directory.
+type thermalZone struct {
+	zone string // N
+	typ  string // sensor type, e.g. "cpu-thermal" or "tj-thermal"
+	dir  string
+}
+
+// listThermalZones returns all thermal zones ordered by zone number.
+func listThermalZones() []thermalZone {
+	dirs, _ := filepath.Glob("/sys/class/thermal/thermal_zone*")
+
+	var zones []thermalZone
+	for _, dir := range dirs {
+		zone := strings.TrimPrefix(filepath.Base(dir), "thermal_zone")
+		if _, err := strconv.Atoi(zone); err != nil {
+			continue
+		}
+		zones = append(zones, thermalZone{
+			zone: zone,
+			typ:  readSysString(filepath.Join(dir, "type")),
+			dir:  dir,
+		})
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		a, _ := strconv.Atoi(zones[i].zone)
+		b, _ := strconv.Atoi(zones[j].zone)
+		return a < b
+	})
+	return zones
+}
+
+// ThermalCollector collects the temperature and trip points of every thermal zone.
+type ThermalCollector struct {
+	tempDesc *prometheus.Desc
+	tripDesc *prometheus.Desc
+}
+
+// NewThermalCollector creates a new ThermalCollector.
+func NewThermalCollector() *ThermalCollector {
+	return &ThermalCollector{
+		tempDesc: prometheus.NewDesc(
+			"thermal_zone_temperature_celsius",
+			"Temperature of the thermal zone in degrees Celsius",
+			[]string{"zone", "type"}, nil,
+		),
+		tripDesc: prometheus.NewDesc(
+			"thermal_zone_trip_point_celsius",
+			"Trip point temperature of the thermal zone in degrees Celsius (trip_type: active, passive, hot, critical)",
+			[]string{"zone", "type", "trip", "trip_type"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ThermalCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tempDesc
+	ch <- c.tripDesc
+}
+
+// Collect reads /sys/class/thermal and sends per-zone temperatures to the channel.
+// Zones whose sensor cannot be read (e.g. powered down) are skipped.
+func (c *ThermalCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, z := range listThermalZones() {
+		temp, ok := readThermalTemp(filepath.Join(z.dir, "temp"))
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.tempDesc, prometheus.GaugeValue, temp, z.zone, z.typ)
+
+		trips, _ := filepath.Glob(filepath.Join(z.dir, "trip_point_*_temp"))
+		for _, path := range trips {
+			trip := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "trip_point_"), "_temp")
+			tripTemp, ok := readThermalTemp(path)
+			if !ok {
+				continue
+			}
+			tripType := readSysString(filepath.Join(z.dir, "trip_point_"+trip+"_type"))
+			ch <- prometheus.MustNewConstMetric(c.tripDesc, prometheus.GaugeValue, tripTemp, z.zone, z.typ, trip, tripType)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ethtool netlink module EEPROM request from <linux/ethtool_netlink.h>.
+const (
+	ethtoolMsgModuleEEPROMGet = 31
+
+	ethtoolAHeader               = 1
+	ethtoolAHeaderDevName        = 2
+	ethtoolAModuleEEPROMOffset   = 2
+	ethtoolAModuleEEPROMLength   = 3
+	ethtoolAModuleEEPROMPage     = 4
+	ethtoolAModuleEEPROMBank     = 5
+	ethtoolAModuleEEPROMI2CAddr  = 6
+	ethtoolAModuleEEPROMData     = 7
+	moduleI2CAddress             = 0x50 // A0h
+	moduleDiagnosticsI2CAddress  = 0x51 // A2h, SFF-8472 only
+	modulePageBytes              = 128
+	moduleRegisterPowerUnitWatts = 1e-7 // 0.1 uW
+	moduleRegisterBiasUnitAmps   = 2e-6 // 2 uA
+	moduleRegisterVoltageUnit    = 1e-4 // 100 uV
+)
+
+// SFF-8024 identifiers (byte 0 of the module EEPROM) grouped by memory map.
+var (
+	sff8472Identifiers = map[byte]string{0x03: "SFP"}
+	sff8636Identifiers = map[byte]string{0x0C: "QSFP", 0x0D: "QSFP+", 0x11: "QSFP28"}
+	cmisIdentifiers    = map[byte]string{0x18: "QSFP-DD", 0x19: "OSFP", 0x1E: "QSFP+ (CMIS)"}
+)
+
+// transceiverDiagnostics is the decoded digital diagnostic monitoring (DOM) data of a module.
+type transceiverDiagnostics struct {
+	identifier, vendor, partNumber, serial string
+
+	temperature, voltage float64
+	hasTemperature       bool
+	txPower, rxPower     []float64 // per lane, watts
+	txBias               []float64 // per lane, amperes
+}
+
+// TransceiverCollector collects SFP/QSFP transceiver diagnostics (the data shown by
+// ethtool -m) using the ethtool netlink module EEPROM interface.
+type TransceiverCollector struct {
+	infoDesc        *prometheus.Desc
+	temperatureDesc *prometheus.Desc
+	voltageDesc     *prometheus.Desc
+	txPowerDesc     *prometheus.Desc
+	rxPowerDesc     *prometheus.Desc
+	txBiasDesc      *prometheus.Desc
+
+	interfaceInclude *regexp.Regexp
+	interfaceExclude *regexp.Regexp
+}
+
+// NewTransceiverCollector creates a new TransceiverCollector.
+// Interfaces are filtered like in NetworkCollector. A nil regexp disables the respective filter.
+func NewTransceiverCollector(interfaceInclude, interfaceExclude *regexp.Regexp) *TransceiverCollector {
+	labels := []string{"interface"}
+	laneLabels := []string{"interface", "lane"}
+	return &TransceiverCollector{
+		infoDesc: prometheus.NewDesc(
+			"transceiver_info",
+			"Transceiver module type and identity (always 1)",
+			[]string{"interface", "identifier", "vendor", "part_number", "serial"}, nil,
+		),
+		temperatureDesc: prometheus.NewDesc(
+			"transceiver_temperature_celsius",
+			"Transceiver module temperature in degrees Celsius",
+			labels, nil,
+		),
+		voltageDesc: prometheus.NewDesc(
+			"transceiver_supply_voltage_volts",
+			"Transceiver module supply voltage in volts",
+			labels, nil,
+		),
+		txPowerDesc: prometheus.NewDesc(
+			"transceiver_tx_power_watts",
+			"Transceiver laser output power per lane in watts",
+			laneLabels, nil,
+		),
+		rxPowerDesc: prometheus.NewDesc(
+			"transceiver_rx_power_watts",
+			"Transceiver received optical power per lane in watts",
+			laneLabels, nil,
+		),
+		txBiasDesc: prometheus.NewDesc(
+			"transceiver_tx_bias_amperes",
+			"Transceiver laser bias current per lane in amperes",
+			laneLabels, nil,
+		),
+		interfaceInclude: interfaceInclude,
+		interfaceExclude: interfaceExclude,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *TransceiverCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.temperatureDesc
+	ch <- c.voltageDesc
+	ch <- c.txPowerDesc
+	ch <- c.rxPowerDesc
+	ch <- c.txBiasDesc
+}
+
+// Collect reads the module EEPROM of every monitored interface and sends the diagnostics
+// to the channel. Interfaces without a pluggable module (or whose driver does not expose
+// it) are skipped. Passive copper (DAC) cables report identity but no optical values.
+func (c *TransceiverCollector) Collect(ch chan<- prometheus.Metric) {
+	conn, err := dialGenl("ethtool")
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for _, iface := range listInterfaces(c.interfaceInclude, c.interfaceExclude) {
+		d, err := readTransceiver(conn, iface)
+		if err != nil {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, iface, d.identifier, d.vendor, d.partNumber, d.serial)
+		if d.hasTemperature {
+			ch <- prometheus.MustNewConstMetric(c.temperatureDesc, prometheus.GaugeValue, d.temperature, iface)
+			ch <- prometheus.MustNewConstMetric(c.voltageDesc, prometheus.GaugeValue, d.voltage, iface)
+		}
+		for _, lanes := range []struct {
+			desc   *prometheus.Desc
+			values []float64
+		}{
+			{c.txPowerDesc, d.txPower},
+			{c.rxPowerDesc, d.rxPower},
+			{c.txBiasDesc, d.txBias},
+		} {
+			for i, v := range lanes.values {
+				ch <- prometheus.MustNewConstMetric(lanes.desc, prometheus.GaugeValue, v, iface, strconv.Itoa(i+1))
+			}
+		}
+	}
+}
+
+// readModuleEEPROM reads length bytes at offset of the given page of the module EEPROM.
+// Offsets 0-127 address the lower page, 128-255 the selected upper page. The kernel
+// limits a read to at most 128 bytes within one half, and only page 0 may be read
+// below offset 128.
+func readModuleEEPROM(conn *genlConn, iface string, i2cAddr, page uint8, offset, length uint32) ([]byte, error) {
+	u32 := func(v uint32) []byte { return binary.NativeEndian.AppendUint32(nil, v) }
+
+	attrs, err := conn.request(conn.family, ethtoolMsgModuleEEPROMGet, 1,
+		netlinkAttr(ethtoolAHeader|nlaFNested, netlinkAttr(ethtoolAHeaderDevName, append([]byte(iface), 0))),
+		netlinkAttr(ethtoolAModuleEEPROMOffset, u32(offset)),
+		netlinkAttr(ethtoolAModuleEEPROMLength, u32(length)),
+		netlinkAttr(ethtoolAModuleEEPROMPage, []byte{page}),
+		netlinkAttr(ethtoolAModuleEEPROMBank, []byte{0}),
+		netlinkAttr(ethtoolAModuleEEPROMI2CAddr, []byte{i2cAddr}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attrs {
+		if a.typ == ethtoolAModuleEEPROMData && len(a.value) == int(length) {
+			return a.value, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: short module EEPROM read", iface)
+}
+
+// readTransceiver identifies the module plugged into iface and decodes its diagnostics.
+func readTransceiver(conn *genlConn, iface string) (transceiverDiagnostics, error) {
+	var d transceiverDiagnostics
+
+	// Lower page and upper page 00h, addressed with their SFF offsets (0-255)
+	lower, err := readModuleEEPROM(conn, iface, moduleI2CAddress, 0, 0, modulePageBytes)
+	if err != nil {
+		return d, err
+	}
+	upper, err := readModuleEEPROM(conn, iface, moduleI2CAddress, 0, modulePageBytes, modulePageBytes)
+	if err != nil {
+		return d, err
+	}
+	page0 := append(append(make([]byte, 0, 2*modulePageBytes), lower...), upper...)
+
+	id := page0[0]
+	if name, ok := sff8472Identifiers[id]; ok {
+		d.identifier = name
+		decodeSFF8472(conn, iface, page0, &d)
+		return d, nil
+	}
+	if name, ok := sff8636Identifiers[id]; ok {
+		d.identifier = name
+		decodeSFF8636(page0, &d)
+		return d, nil
+	}
+	if name, ok := cmisIdentifiers[id]; ok {
+		d.identifier = name
+		decodeCMIS(conn, iface, page0, &d)
+		return d, nil
+	}
+	d.identifier = fmt.Sprintf("0x%02x", id)
+	return d, nil
+}
+
+// decodeSFF8472 decodes an SFP module: identity from A0h, diagnostics from A2h.
+func decodeSFF8472(conn *genlConn, iface string, a0 []byte, d *transceiverDiagnostics) {
+	d.vendor = moduleString(a0[20:36])
+	d.partNumber = moduleString(a0[40:56])
+	d.serial = moduleString(a0[68:84])
+
+	// Byte 92 bit 6: digital diagnostic monitoring implemented
+	if a0[92]&0x40 == 0 {
+		return
+	}
+	a2, err := readModuleEEPROM(conn, iface, moduleDiagnosticsI2CAddress, 0, 0, modulePageBytes)
+	if err != nil {
+		return
+	}
+	d.temperature = moduleTemperature(a2[96:98])
+	d.voltage = float64(binary.BigEndian.Uint16(a2[98:100])) * moduleRegisterVoltageUnit
+	d.hasTemperature = true
+	d.txBias = []float64{float64(binary.BigEndian.Uint16(a2[100:102])) * moduleRegisterBiasUnitAmps}
+	d.txPower = []float64{float64(binary.BigEndian.Uint16(a2[102:104])) * moduleRegisterPowerUnitWatts}
+	d.rxPower = []float64{float64(binary.BigEndian.Uint16(a2[104:106])) * moduleRegisterPowerUnitWatts}
+}
+
+// decodeSFF8636 decodes a QSFP/QSFP28 module; diagnostics are in the lower page,
+// identity in upper page 00h.
+func decodeSFF8636(p []byte, d *transceiverDiagnostics) {
+	d.vendor = moduleString(p[148:164])
+	d.partNumber = moduleString(p[168:184])
+	d.serial = moduleString(p[196:212])
+
+	d.temperature = moduleTemperature(p[22:24])
+	d.voltage = float64(binary.BigEndian.Uint16(p[26:28])) * moduleRegisterVoltageUnit
+	d.hasTemperature = true
+	d.rxPower = moduleLanes(p[34:42], 4, moduleRegisterPowerUnitWatts)
+	d.txBias = moduleLanes(p[42:50], 4, moduleRegisterBiasUnitAmps)
+	d.txPower = moduleLanes(p[50:58], 4, moduleRegisterPowerUnitWatts)
+}
+
+// decodeCMIS decodes a CMIS module (QSFP-DD, OSFP, QSFP112 as used for 200GbE):
+// module monitors are in the lower page, identity in page 00h, and lane monitors
+// in page 11h. Flat-memory modules (passive copper) have no lane monitors.
+func decodeCMIS(conn *genlConn, iface string, p []byte, d *transceiverDiagnostics) {
+	d.vendor = moduleString(p[129:145])
+	d.partNumber = moduleString(p[148:164])
+	d.serial = moduleString(p[166:182])
+
+	d.temperature = moduleTemperature(p[14:16])
+	d.voltage = float64(binary.BigEndian.Uint16(p[16:18])) * moduleRegisterVoltageUnit
+	d.hasTemperature = true
+
+	// Byte 2 bit 7: flat memory (no paged upper memory)
+	if p[2]&0x80 != 0 {
+		return
+	}
+
+	// Media lane count of the first application descriptor (byte 88, bits 3-0)
+	lanes := int(p[88] & 0x0F)
+	if lanes == 0 || lanes > 8 {
+		lanes = 8
+	}
+
+	page11, err := readModuleEEPROM(conn, iface, moduleI2CAddress, 0x11, modulePageBytes, modulePageBytes)
+	if err != nil {
+		return
+	}
+	// Page 11h bytes 154-169: TX power, 170-185: TX bias, 186-201: RX power (offsets relative to 128)
+	d.txPower = moduleLanes(page11[154-128:170-128], lanes, moduleRegisterPowerUnitWatts)
+	d.rxPower = moduleLanes(page11[186-128:202-128], lanes, moduleRegisterPowerUnitWatts)
+
+	// Page 01h byte 160 bits 4-3: TX bias current multiplier (1, 2, or 4)
+	biasUnit := moduleRegisterBiasUnitAmps
+	if page1, err := readModuleEEPROM(conn, iface, moduleI2CAddress, 0x01, 160, 1); err == nil {
+		biasUnit *= float64(int(1) << ((page1[0] >> 3) & 0x03))
+	}
+	d.txBias = moduleLanes(page11[170-128:186-128], lanes, biasUnit)
+}
+
+// moduleTemperature decodes a signed 16-bit temperature in 1/256 degrees Celsius.
+func moduleTemperature(b []byte) float64 {
+	return float64(int16(binary.BigEndian.Uint16(b))) / 256
+}
+
+// moduleLanes decodes up to n consecutive big-endian 16-bit lane monitors scaled by unit.
+func moduleLanes(b []byte, n int, unit float64) []float64 {
+	values := make([]float64, 0, n)
+	for i := 0; i < n && 2*i+2 <= len(b); i++ {
+		values = append(values, float64(binary.BigEndian.Uint16(b[2*i:]))*unit)
+	}
+	return values
+}
+
+// moduleString decodes a space-padded ASCII EEPROM field.
+func moduleString(b []byte) string {
+	return strings.TrimSpace(nullTerminated(b))
+}
@@ -0,0 +1,132 @@
+package collectors
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DefaultTritonURL is the metrics endpoint of a Triton Inference Server with the
+// default --metrics-port.
+const DefaultTritonURL = "http://localhost:8002/metrics"
+
+// TritonCollector scrapes the metrics endpoint of a co-located Triton Inference
+// Server and re-exposes the selected series, so they carry the exporter's host
+// label and need no scrape target of their own.
+type TritonCollector struct {
+	upDesc       *prometheus.Desc
+	durationDesc *prometheus.Desc
+
+	url     string
+	include *regexp.Regexp
+	client  *http.Client
+}
+
+// NewTritonCollector creates a new TritonCollector scraping metricsURL with the
+// given timeout. Only metric families whose name matches include are re-exposed;
+// a nil regexp selects all of them.
+func NewTritonCollector(metricsURL string, include *regexp.Regexp, timeout time.Duration) *TritonCollector {
+	return &TritonCollector{
+		upDesc: prometheus.NewDesc(
+			"triton_up",
+			"Whether the last scrape of the Triton metrics endpoint succeeded (1 = success)",
+			nil, nil,
+		),
+		durationDesc: prometheus.NewDesc(
+			"triton_scrape_duration_seconds",
+			"Duration of the last scrape of the Triton metrics endpoint in seconds",
+			nil, nil,
+		),
+		url:     metricsURL,
+		include: include,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Describe sends no descriptors: the Triton series are not known in advance, so
+// the collector is registered as unchecked.
+func (c *TritonCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect scrapes Triton and sends the selected series to the channel. A host
+// label set by Triton is renamed to exported_host so it does not clash with the
+// exporter's own.
+func (c *TritonCollector) Collect(ch chan<- prometheus.Metric) {
+	start := time.Now()
+	families, err := c.scrape()
+	ch <- prometheus.MustNewConstMetric(c.durationDesc, prometheus.GaugeValue, time.Since(start).Seconds())
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 0)
+		ch <- prometheus.NewInvalidMetric(c.upDesc, fmt.Errorf("scraping %s: %w", c.url, err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 1)
+
+	for _, mf := range families {
+		if c.include != nil && !c.include.MatchString(mf.GetName()) {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			metric, err := constMetricFromDTO(mf, m)
+			if err != nil {
+				ch <- prometheus.NewInvalidMetric(c.upDesc, fmt.Errorf("%s: %w", mf.GetName(), err))
+				continue
+			}
+			ch <- metric
+		}
+	}
+}
+
+// scrape fetches the Triton metrics.
+func (c *TritonCollector) scrape() ([]*dto.MetricFamily, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return fetchMetricFamilies(c.client, req)
+}
+
+// constMetricFromDTO converts a decoded metric of family mf back into a constant
+// metric. Untyped metrics are kept untyped; native histogram buckets are dropped.
+func constMetricFromDTO(mf *dto.MetricFamily, m *dto.Metric) (prometheus.Metric, error) {
+	names := make([]string, 0, len(m.GetLabel()))
+	values := make([]string, 0, len(m.GetLabel()))
+	for _, l := range m.GetLabel() {
+		name := l.GetName()
+		if name == "host" {
+			name = "exported_host"
+		}
+		names = append(names, name)
+		values = append(values, l.GetValue())
+	}
+	desc := prometheus.NewDesc(mf.GetName(), mf.GetHelp(), names, nil)
+
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		return prometheus.NewConstMetric(desc, prometheus.CounterValue, m.GetCounter().GetValue(), values...)
+	case dto.MetricType_GAUGE:
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.GetGauge().GetValue(), values...)
+	case dto.MetricType_SUMMARY:
+		s := m.GetSummary()
+		quantiles := make(map[float64]float64, len(s.GetQuantile()))
+		for _, q := range s.GetQuantile() {
+			quantiles[q.GetQuantile()] = q.GetValue()
+		}
+		return prometheus.NewConstSummary(desc, s.GetSampleCount(), s.GetSampleSum(), quantiles, values...)
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		h := m.GetHistogram()
+		buckets := make(map[float64]uint64, len(h.GetBucket()))
+		for _, b := range h.GetBucket() {
+			if !math.IsInf(b.GetUpperBound(), 1) {
+				buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+			}
+		}
+		return prometheus.NewConstHistogram(desc, h.GetSampleCount(), h.GetSampleSum(), buckets, values...)
+	default:
+		return prometheus.NewConstMetric(desc, prometheus.UntypedValue, m.GetUntyped().GetValue(), values...)
+	}
+}
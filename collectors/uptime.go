@@ -0,0 +1,78 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UptimeCollector collects the system boot time and uptime.
+type UptimeCollector struct {
+	bootTimeDesc *prometheus.Desc
+	uptimeDesc   *prometheus.Desc
+}
+
+// NewUptimeCollector creates a new UptimeCollector.
+func NewUptimeCollector() *UptimeCollector {
+	return &UptimeCollector{
+		bootTimeDesc: prometheus.NewDesc(
+			"node_boot_time_seconds",
+			"System boot time as a Unix timestamp in seconds",
+			nil, nil,
+		),
+		uptimeDesc: prometheus.NewDesc(
+			"node_uptime_seconds",
+			"Time since the system booted in seconds, excluding time spent suspended",
+			nil, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *UptimeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.bootTimeDesc
+	ch <- c.uptimeDesc
+}
+
+// Collect reads the boot time from /proc/stat and the uptime from /proc/uptime and
+// sends them to the channel.
+func (c *UptimeCollector) Collect(ch chan<- prometheus.Metric) {
+	if btime, ok := readBootTime(); ok {
+		ch <- prometheus.MustNewConstMetric(c.bootTimeDesc, prometheus.GaugeValue, btime)
+	}
+
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return
+	}
+	// Format: "<uptime> <idle time of all CPUs>"
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return
+	}
+	if uptime, err := strconv.ParseFloat(fields[0], 64); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.uptimeDesc, prometheus.GaugeValue, uptime)
+	}
+}
+
+// readBootTime returns the btime line of /proc/stat (seconds since the epoch).
+func readBootTime() (float64, bool) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			v, err := strconv.ParseFloat(fields[1], 64)
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,139 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VLANBridgeCollector collects VLAN sub-interface and Linux bridge topology, so that
+// network_* metrics of those interfaces can be joined with their parent or bridge.
+type VLANBridgeCollector struct {
+	vlanInfoDesc    *prometheus.Desc
+	bridgeInfoDesc  *prometheus.Desc
+	bridgePortsDesc *prometheus.Desc
+	portStateDesc   *prometheus.Desc
+
+	interfaceInclude *regexp.Regexp
+	interfaceExclude *regexp.Regexp
+}
+
+// NewVLANBridgeCollector creates a new VLANBridgeCollector.
+// Interfaces are filtered like in NetworkCollector. A nil regexp disables the respective filter.
+func NewVLANBridgeCollector(interfaceInclude, interfaceExclude *regexp.Regexp) *VLANBridgeCollector {
+	return &VLANBridgeCollector{
+		vlanInfoDesc: prometheus.NewDesc(
+			"network_vlan_info",
+			"VLAN sub-interface with its parent interface and VLAN ID (always 1)",
+			[]string{"interface", "parent", "vlan_id"}, nil,
+		),
+		bridgeInfoDesc: prometheus.NewDesc(
+			"network_bridge_info",
+			"Linux bridge and whether it runs the spanning tree protocol (always 1)",
+			[]string{"bridge", "stp"}, nil,
+		),
+		bridgePortsDesc: prometheus.NewDesc(
+			"network_bridge_ports",
+			"Number of interfaces attached to the bridge",
+			[]string{"bridge"}, nil,
+		),
+		portStateDesc: prometheus.NewDesc(
+			"network_bridge_port_state",
+			"STP state of bridge port (0 = disabled, 1 = listening, 2 = learning, 3 = forwarding, 4 = blocking)",
+			[]string{"bridge", "interface"}, nil,
+		),
+		interfaceInclude: interfaceInclude,
+		interfaceExclude: interfaceExclude,
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *VLANBridgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.vlanInfoDesc
+	ch <- c.bridgeInfoDesc
+	ch <- c.bridgePortsDesc
+	ch <- c.portStateDesc
+}
+
+// Collect sends VLAN and bridge topology of the monitored interfaces to the channel.
+func (c *VLANBridgeCollector) Collect(ch chan<- prometheus.Metric) {
+	monitored := make(map[string]bool)
+	for _, iface := range listInterfaces(c.interfaceInclude, c.interfaceExclude) {
+		monitored[iface] = true
+	}
+
+	for _, v := range readVLANConfig("/proc/net/vlan/config") {
+		if monitored[v.name] {
+			ch <- prometheus.MustNewConstMetric(c.vlanInfoDesc, prometheus.GaugeValue, 1, v.name, v.parent, v.id)
+		}
+	}
+
+	for iface := range monitored {
+		bridgeDir := filepath.Join("/sys/class/net", iface, "bridge")
+		if !fileExists(bridgeDir) {
+			continue
+		}
+
+		stp := "false"
+		if readSysString(filepath.Join(bridgeDir, "stp_state")) != "0" {
+			stp = "true"
+		}
+		ch <- prometheus.MustNewConstMetric(c.bridgeInfoDesc, prometheus.GaugeValue, 1, iface, stp)
+
+		ports, _ := os.ReadDir(filepath.Join("/sys/class/net", iface, "brif"))
+		ch <- prometheus.MustNewConstMetric(c.bridgePortsDesc, prometheus.GaugeValue, float64(len(ports)), iface)
+
+		for _, p := range ports {
+			if !monitored[p.Name()] {
+				continue
+			}
+			state, err := strconv.ParseFloat(readSysString(filepath.Join("/sys/class/net", iface, "brif", p.Name(), "state")), 64)
+			if err != nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.portStateDesc, prometheus.GaugeValue, state, iface, p.Name())
+		}
+	}
+}
+
+// vlanConfig is one VLAN sub-interface listed in /proc/net/vlan/config.
+type vlanConfig struct {
+	name, id, parent string
+}
+
+// readVLANConfig parses /proc/net/vlan/config, which exists while the 8021q module is loaded:
+//
+//	VLAN Dev name	 | VLAN ID
+//	Name-Type: VLAN_NAME_TYPE_RAW_PLUS_VID_NO_PAD
+//	enp1s0f0np0.100  | 100  | enp1s0f0np0
+func readVLANConfig(path string) []vlanConfig {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var vlans []vlanConfig
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) != 3 {
+			continue
+		}
+		v := vlanConfig{
+			name:   strings.TrimSpace(fields[0]),
+			id:     strings.TrimSpace(fields[1]),
+			parent: strings.TrimSpace(fields[2]),
+		}
+		if _, err := strconv.Atoi(v.id); err != nil {
+			continue
+		}
+		vlans = append(vlans, v)
+	}
+	return vlans
+}
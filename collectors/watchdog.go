@@ -0,0 +1,135 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// watchdogClassDir lists hardware watchdog devices (requires CONFIG_WATCHDOG_SYSFS).
+const watchdogClassDir = "/sys/class/watchdog"
+
+// watchdogCardReset is WDIOF_CARDRESET from <linux/watchdog.h>: set in bootstatus
+// when the last reboot was caused by the watchdog.
+const watchdogCardReset = 0x0020
+
+// WatchdogCollector collects the state of hardware watchdog devices.
+type WatchdogCollector struct {
+	infoDesc       *prometheus.Desc
+	activeDesc     *prometheus.Desc
+	timeoutDesc    *prometheus.Desc
+	pretimeoutDesc *prometheus.Desc
+	timeLeftDesc   *prometheus.Desc
+	nowayoutDesc   *prometheus.Desc
+	bootResetDesc  *prometheus.Desc
+	bootStatusDesc *prometheus.Desc
+}
+
+// NewWatchdogCollector creates a new WatchdogCollector.
+func NewWatchdogCollector() *WatchdogCollector {
+	labels := []string{"watchdog"}
+	return &WatchdogCollector{
+		infoDesc: prometheus.NewDesc(
+			"watchdog_info",
+			"Hardware watchdog device, always 1",
+			[]string{"watchdog", "identity"}, nil,
+		),
+		activeDesc: prometheus.NewDesc(
+			"watchdog_active",
+			"Whether the watchdog is armed, i.e. opened by systemd or a watchdog daemon (1 = armed)",
+			labels, nil,
+		),
+		timeoutDesc: prometheus.NewDesc(
+			"watchdog_timeout_seconds",
+			"Time without a keepalive after which the watchdog resets the system in seconds",
+			labels, nil,
+		),
+		pretimeoutDesc: prometheus.NewDesc(
+			"watchdog_pretimeout_seconds",
+			"Time before the timeout at which the pretimeout governor is notified in seconds",
+			labels, nil,
+		),
+		timeLeftDesc: prometheus.NewDesc(
+			"watchdog_time_left_seconds",
+			"Time left until the watchdog resets the system in seconds",
+			labels, nil,
+		),
+		nowayoutDesc: prometheus.NewDesc(
+			"watchdog_nowayout",
+			"Whether the watchdog cannot be stopped once armed (1 = nowayout)",
+			labels, nil,
+		),
+		bootResetDesc: prometheus.NewDesc(
+			"watchdog_last_boot_reset",
+			"Whether the last reboot was caused by this watchdog (1 = watchdog reset)",
+			labels, nil,
+		),
+		bootStatusDesc: prometheus.NewDesc(
+			"watchdog_boot_status",
+			"Raw WDIOF_* boot status flags reported by the watchdog driver",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *WatchdogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.activeDesc
+	ch <- c.timeoutDesc
+	ch <- c.pretimeoutDesc
+	ch <- c.timeLeftDesc
+	ch <- c.nowayoutDesc
+	ch <- c.bootResetDesc
+	ch <- c.bootStatusDesc
+}
+
+// Collect reads /sys/class/watchdog and sends the watchdog state to the channel.
+// Attributes not supported by the driver are omitted. If no watchdog is present,
+// no metrics are emitted.
+func (c *WatchdogCollector) Collect(ch chan<- prometheus.Metric) {
+	entries, err := os.ReadDir(watchdogClassDir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		dir := filepath.Join(watchdogClassDir, name)
+
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, name, readSysString(filepath.Join(dir, "identity")))
+
+		if state := readSysString(filepath.Join(dir, "state")); state != "" {
+			active := 0.0
+			if state == "active" {
+				active = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.activeDesc, prometheus.GaugeValue, active, name)
+		}
+
+		for _, m := range []struct {
+			desc *prometheus.Desc
+			attr string
+		}{
+			{c.timeoutDesc, "timeout"},
+			{c.pretimeoutDesc, "pretimeout"},
+			{c.timeLeftDesc, "timeleft"},
+			{c.nowayoutDesc, "nowayout"},
+		} {
+			if v, ok := readProcSysFloat(filepath.Join(dir, m.attr)); ok {
+				ch <- prometheus.MustNewConstMetric(m.desc, prometheus.GaugeValue, v, name)
+			}
+		}
+
+		if status, err := strconv.ParseUint(readSysString(filepath.Join(dir, "bootstatus")), 10, 32); err == nil {
+			reset := 0.0
+			if status&watchdogCardReset != 0 {
+				reset = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.bootResetDesc, prometheus.GaugeValue, reset, name)
+			ch <- prometheus.MustNewConstMetric(c.bootStatusDesc, prometheus.GaugeValue, float64(status), name)
+		}
+	}
+}
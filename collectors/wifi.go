@@ -0,0 +1,322 @@
+package collectors
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// nl80211 commands and attributes from <linux/nl80211.h>.
+const (
+	nl80211CmdGetInterface = 5
+	nl80211CmdGetStation   = 17
+
+	nl80211AttrIfindex   = 3
+	nl80211AttrIftype    = 5
+	nl80211AttrMAC       = 6
+	nl80211AttrStaInfo   = 21
+	nl80211AttrWiphyFreq = 38
+	nl80211AttrSSID      = 52
+
+	nl80211IftypeStation = 2
+
+	nl80211StaInfoSignal     = 7
+	nl80211StaInfoTxBitrate  = 8
+	nl80211StaInfoRxBitrate  = 14
+	nl80211RateInfoBitrate   = 1 // u16, 100 kbit/s
+	nl80211RateInfoBitrate32 = 5 // u32, 100 kbit/s
+)
+
+// WifiCollector collects signal quality and link state of wireless interfaces
+// (e.g. wlP9s9 on the DGX Spark).
+type WifiCollector struct {
+	connectedDesc *prometheus.Desc
+	infoDesc      *prometheus.Desc
+	signalDesc    *prometheus.Desc
+	qualityDesc   *prometheus.Desc
+	noiseDesc     *prometheus.Desc
+	frequencyDesc *prometheus.Desc
+	rxBitrateDesc *prometheus.Desc
+	txBitrateDesc *prometheus.Desc
+}
+
+// NewWifiCollector creates a new WifiCollector.
+func NewWifiCollector() *WifiCollector {
+	labels := []string{"interface"}
+	return &WifiCollector{
+		connectedDesc: prometheus.NewDesc(
+			"wifi_connected",
+			"Whether the wireless interface is associated with an access point (1 = connected, 0 = not connected)",
+			labels, nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			"wifi_info",
+			"Access point the wireless interface is associated with (always 1)",
+			append(labels, "ssid", "bssid"), nil,
+		),
+		signalDesc: prometheus.NewDesc(
+			"wifi_signal_dbm",
+			"Received signal strength in dBm",
+			labels, nil,
+		),
+		qualityDesc: prometheus.NewDesc(
+			"wifi_link_quality",
+			"Link quality as reported by the driver in /proc/net/wireless",
+			labels, nil,
+		),
+		noiseDesc: prometheus.NewDesc(
+			"wifi_noise_dbm",
+			"Noise level in dBm",
+			labels, nil,
+		),
+		frequencyDesc: prometheus.NewDesc(
+			"wifi_frequency_mhz",
+			"Operating frequency of the wireless link in MHz",
+			labels, nil,
+		),
+		rxBitrateDesc: prometheus.NewDesc(
+			"wifi_receive_bitrate_bps",
+			"Bitrate of the last received frame in bits per second",
+			labels, nil,
+		),
+		txBitrateDesc: prometheus.NewDesc(
+			"wifi_transmit_bitrate_bps",
+			"Bitrate of the last transmitted frame in bits per second",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *WifiCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connectedDesc
+	ch <- c.infoDesc
+	ch <- c.signalDesc
+	ch <- c.qualityDesc
+	ch <- c.noiseDesc
+	ch <- c.frequencyDesc
+	ch <- c.rxBitrateDesc
+	ch <- c.txBitrateDesc
+}
+
+// wirelessStats holds one interface line of /proc/net/wireless.
+type wirelessStats struct {
+	quality, level, noise float64
+}
+
+// Collect reads /proc/net/wireless and the nl80211 link state of every wireless
+// interface and sends the results to the channel. Hosts without wireless
+// interfaces emit no metrics.
+func (c *WifiCollector) Collect(ch chan<- prometheus.Metric) {
+	ifaces := wirelessInterfaces()
+	if len(ifaces) == 0 {
+		return
+	}
+
+	stats := readProcNetWireless("/proc/net/wireless")
+
+	conn, err := dialGenl("nl80211")
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.connectedDesc, fmt.Errorf("nl80211: %w", err))
+	} else {
+		defer conn.Close()
+	}
+
+	for _, iface := range ifaces {
+		// /proc/net/wireless only lists associated interfaces; 0 dBm / -256 mean "not reported"
+		s, hasStats := stats[iface]
+		if hasStats {
+			ch <- prometheus.MustNewConstMetric(c.qualityDesc, prometheus.GaugeValue, s.quality, iface)
+			if s.noise < 0 && s.noise > -256 {
+				ch <- prometheus.MustNewConstMetric(c.noiseDesc, prometheus.GaugeValue, s.noise, iface)
+			}
+		}
+
+		var link wifiLink
+		if conn != nil {
+			link, err = readWifiLink(conn, iface)
+			if err != nil {
+				ch <- prometheus.NewInvalidMetric(c.connectedDesc, err)
+			}
+		}
+		if conn == nil || err != nil {
+			if hasStats && s.level < 0 {
+				ch <- prometheus.MustNewConstMetric(c.signalDesc, prometheus.GaugeValue, s.level, iface)
+			}
+			continue
+		}
+		if !link.station {
+			continue
+		}
+
+		connected := 0.0
+		if link.connected {
+			connected = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.connectedDesc, prometheus.GaugeValue, connected, iface)
+		if !link.connected {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, iface, link.ssid, link.bssid)
+		for _, m := range []struct {
+			desc  *prometheus.Desc
+			value float64
+		}{
+			{c.signalDesc, link.signal},
+			{c.frequencyDesc, link.frequency},
+			{c.rxBitrateDesc, link.rxBitrate},
+			{c.txBitrateDesc, link.txBitrate},
+		} {
+			if m.value != 0 {
+				ch <- prometheus.MustNewConstMetric(m.desc, prometheus.GaugeValue, m.value, iface)
+			}
+		}
+	}
+}
+
+// wirelessInterfaces returns the interfaces in /sys/class/net that have a wireless extension.
+func wirelessInterfaces() []string {
+	entries, err := os.ReadDir("/sys/class/net")
+	if err != nil {
+		return nil
+	}
+
+	var ifaces []string
+	for _, e := range entries {
+		if _, err := os.Stat(filepath.Join("/sys/class/net", e.Name(), "wireless")); err == nil {
+			ifaces = append(ifaces, e.Name())
+		}
+	}
+	return ifaces
+}
+
+// readProcNetWireless parses /proc/net/wireless, keyed by interface name.
+func readProcNetWireless(path string) map[string]wirelessStats {
+	stats := make(map[string]wirelessStats)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return stats
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: wlP9s9: 0000   54.  -56.  -256        0      0      0      0     12        0
+		iface, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 4 {
+			continue
+		}
+
+		var values [3]float64
+		valid := true
+		for i := range values {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(fields[i+1], "."), 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			values[i] = v
+		}
+		if valid {
+			stats[strings.TrimSpace(iface)] = wirelessStats{values[0], values[1], values[2]}
+		}
+	}
+	return stats
+}
+
+// wifiLink holds the link state of a wireless interface as reported by nl80211.
+type wifiLink struct {
+	station              bool // managed (client) mode; other modes have no single link
+	connected            bool
+	ssid, bssid          string
+	signal, frequency    float64
+	rxBitrate, txBitrate float64
+}
+
+// readWifiLink returns the link state of iface, as "iw dev <iface> link" shows it:
+// the SSID and frequency from the interface and the BSSID, signal and bitrates
+// from the station entry of the access point.
+func readWifiLink(conn *genlConn, iface string) (wifiLink, error) {
+	ifindex, ok := readSysCounter(filepath.Join("/sys/class/net", iface, "ifindex"))
+	if !ok {
+		return wifiLink{}, fmt.Errorf("%s: no ifindex", iface)
+	}
+	ifindexAttr := netlinkAttr(nl80211AttrIfindex, binary.NativeEndian.AppendUint32(nil, uint32(ifindex)))
+
+	attrs, err := conn.request(conn.family, nl80211CmdGetInterface, 0, ifindexAttr)
+	if err != nil {
+		return wifiLink{}, fmt.Errorf("%s: nl80211 get interface: %w", iface, err)
+	}
+	var link wifiLink
+	for _, a := range attrs {
+		switch {
+		case a.typ == nl80211AttrIftype && len(a.value) >= 4:
+			link.station = binary.NativeEndian.Uint32(a.value) == nl80211IftypeStation
+		case a.typ == nl80211AttrSSID:
+			link.ssid = string(a.value)
+		case a.typ == nl80211AttrWiphyFreq && len(a.value) >= 4:
+			link.frequency = float64(binary.NativeEndian.Uint32(a.value))
+		}
+	}
+	if !link.station {
+		return link, nil
+	}
+
+	// In managed mode the only station is the access point
+	stations, err := conn.dump(conn.family, nl80211CmdGetStation, 0, ifindexAttr)
+	if err != nil {
+		return wifiLink{}, fmt.Errorf("%s: nl80211 get station: %w", iface, err)
+	}
+	for _, station := range stations {
+		for _, a := range station {
+			switch {
+			case a.typ == nl80211AttrMAC && len(a.value) == 6:
+				link.connected = true
+				link.bssid = net.HardwareAddr(a.value).String()
+			case a.typ == nl80211AttrStaInfo:
+				for _, info := range parseNestedAttrs(a.value) {
+					switch {
+					case info.typ == nl80211StaInfoSignal && len(info.value) >= 1:
+						link.signal = float64(int8(info.value[0]))
+					case info.typ == nl80211StaInfoTxBitrate:
+						link.txBitrate = nl80211Bitrate(info.value)
+					case info.typ == nl80211StaInfoRxBitrate:
+						link.rxBitrate = nl80211Bitrate(info.value)
+					}
+				}
+			}
+		}
+		if link.connected {
+			break
+		}
+	}
+	return link, nil
+}
+
+// nl80211Bitrate converts a nested NL80211_STA_INFO_*_BITRATE attribute to bits
+// per second, preferring the 32-bit rate that also covers rates above 6.5 Gbit/s.
+func nl80211Bitrate(b []byte) float64 {
+	var rate float64
+	for _, a := range parseNestedAttrs(b) {
+		switch {
+		case a.typ == nl80211RateInfoBitrate32 && len(a.value) >= 4:
+			return float64(binary.NativeEndian.Uint32(a.value)) * 100e3
+		case a.typ == nl80211RateInfoBitrate && len(a.value) >= 2:
+			rate = float64(binary.NativeEndian.Uint16(a.value)) * 100e3
+		}
+	}
+	return rate
+}
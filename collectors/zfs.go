@@ -0,0 +1,175 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// zfsKstatDir is the procfs directory where the SPL exposes ZFS kstats.
+const zfsKstatDir = "/proc/spl/kstat/zfs"
+
+// zfsPoolStates are the possible pool health states reported by the pool state kstat.
+var zfsPoolStates = []string{"ONLINE", "DEGRADED", "FAULTED", "OFFLINE", "UNAVAIL", "REMOVED", "SUSPENDED"}
+
+// ZFSCollector collects ZFS ARC statistics and pool health from /proc/spl/kstat/zfs.
+type ZFSCollector struct {
+	arcSizeDesc   *prometheus.Desc
+	arcTargetDesc *prometheus.Desc
+	arcMaxDesc    *prometheus.Desc
+	arcHitsDesc   *prometheus.Desc
+	arcMissesDesc *prometheus.Desc
+	l2HitsDesc    *prometheus.Desc
+	l2MissesDesc  *prometheus.Desc
+	poolStateDesc *prometheus.Desc
+}
+
+// NewZFSCollector creates a new ZFSCollector.
+func NewZFSCollector() *ZFSCollector {
+	return &ZFSCollector{
+		arcSizeDesc: prometheus.NewDesc(
+			"zfs_arc_size_bytes",
+			"Current size of the ZFS ARC in bytes",
+			nil, nil,
+		),
+		arcTargetDesc: prometheus.NewDesc(
+			"zfs_arc_target_size_bytes",
+			"Target size of the ZFS ARC in bytes",
+			nil, nil,
+		),
+		arcMaxDesc: prometheus.NewDesc(
+			"zfs_arc_max_size_bytes",
+			"Maximum size of the ZFS ARC in bytes",
+			nil, nil,
+		),
+		arcHitsDesc: prometheus.NewDesc(
+			"zfs_arc_hits_total",
+			"Total number of ZFS ARC hits",
+			nil, nil,
+		),
+		arcMissesDesc: prometheus.NewDesc(
+			"zfs_arc_misses_total",
+			"Total number of ZFS ARC misses",
+			nil, nil,
+		),
+		l2HitsDesc: prometheus.NewDesc(
+			"zfs_l2arc_hits_total",
+			"Total number of ZFS L2ARC hits",
+			nil, nil,
+		),
+		l2MissesDesc: prometheus.NewDesc(
+			"zfs_l2arc_misses_total",
+			"Total number of ZFS L2ARC misses",
+			nil, nil,
+		),
+		poolStateDesc: prometheus.NewDesc(
+			"zfs_pool_state",
+			"ZFS pool health state (1 for the current state)",
+			[]string{"pool", "state"}, nil,
+		),
+	}
+}
+
+// Describe sends metric descriptors to the channel.
+func (c *ZFSCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.arcSizeDesc
+	ch <- c.arcTargetDesc
+	ch <- c.arcMaxDesc
+	ch <- c.arcHitsDesc
+	ch <- c.arcMissesDesc
+	ch <- c.l2HitsDesc
+	ch <- c.l2MissesDesc
+	ch <- c.poolStateDesc
+}
+
+// Collect reads ZFS kstats and sends ARC and pool metrics to the channel.
+// If the ZFS module is not loaded, no metrics are emitted.
+func (c *ZFSCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectARC(ch)
+	c.collectPools(ch)
+}
+
+// collectARC reports ARC size and hit/miss counters from the arcstats kstat.
+func (c *ZFSCollector) collectARC(ch chan<- prometheus.Metric) {
+	stats, err := readZFSKstat(filepath.Join(zfsKstatDir, "arcstats"))
+	if err != nil {
+		return
+	}
+
+	metrics := []struct {
+		desc      *prometheus.Desc
+		key       string
+		valueType prometheus.ValueType
+	}{
+		{c.arcSizeDesc, "size", prometheus.GaugeValue},
+		{c.arcTargetDesc, "c", prometheus.GaugeValue},
+		{c.arcMaxDesc, "c_max", prometheus.GaugeValue},
+		{c.arcHitsDesc, "hits", prometheus.CounterValue},
+		{c.arcMissesDesc, "misses", prometheus.CounterValue},
+		{c.l2HitsDesc, "l2_hits", prometheus.CounterValue},
+		{c.l2MissesDesc, "l2_misses", prometheus.CounterValue},
+	}
+	for _, m := range metrics {
+		if v, ok := stats[m.key]; ok {
+			ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, v)
+		}
+	}
+}
+
+// collectPools reports the health state of every imported pool.
+func (c *ZFSCollector) collectPools(ch chan<- prometheus.Metric) {
+	stateFiles, err := filepath.Glob(filepath.Join(zfsKstatDir, "*", "state"))
+	if err != nil {
+		return
+	}
+
+	for _, stateFile := range stateFiles {
+		pool := filepath.Base(filepath.Dir(stateFile))
+		current := readSysString(stateFile)
+		if current == "" {
+			continue
+		}
+
+		for _, state := range zfsPoolStates {
+			v := 0.0
+			if state == current {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.poolStateDesc, prometheus.GaugeValue, v, pool, strings.ToLower(state))
+		}
+	}
+}
+
+// readZFSKstat parses a "name type data" kstat file into a map of name -> value.
+func readZFSKstat(path string) (map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := make(map[string]float64)
+	scanner := bufio.NewScanner(f)
+
+	// The first two lines are the kstat header and the column names
+	for line := 0; scanner.Scan(); line++ {
+		if line < 2 {
+			continue
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+		stats[fields[0]] = v
+	}
+
+	return stats, scanner.Err()
+}
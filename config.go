@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// configLabelsKey is the configuration file section holding constant labels added
+// to all metrics, as opposed to flag values.
+const configLabelsKey = "labels"
+
+// loadConfig reads the YAML configuration file at path and applies it to the
+// flags of fs that were not set on the command line. Keys name flags, with nested
+// mappings joined by dots, so
+//
+//	collector:
+//	  probe: true
+//	probe:
+//	  icmp-targets: [spark2]
+//
+// sets -collector.probe and -probe.icmp-targets. Lists are joined with commas.
+// The labels mapping is returned instead of being applied to a flag.
+func loadConfig(fs *flag.FlagSet, path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	labels := make(map[string]string)
+	if raw, ok := doc[configLabelsKey]; ok {
+		m, ok := raw.(map[any]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s must be a mapping", path, configLabelsKey)
+		}
+		for k, v := range m {
+			labels[fmt.Sprint(k)] = fmt.Sprint(v)
+		}
+		delete(doc, configLabelsKey)
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfig("", doc, values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "config" || fs.Lookup(name) == nil {
+			return nil, fmt.Errorf("%s: unknown option %q", path, name)
+		}
+		if set[name] {
+			continue // command-line flags override the file
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return nil, fmt.Errorf("%s: invalid value for %s: %w", path, name, err)
+		}
+	}
+	return labels, nil
+}
+
+// flattenConfig stores the scalar values of the nested mapping m in values, keyed
+// by their dot-joined path below prefix.
+func flattenConfig(prefix string, m map[string]any, values map[string]string) error {
+	for k, v := range m {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[any]any:
+			nested := make(map[string]any, len(v))
+			for nk, nv := range v {
+				nested[fmt.Sprint(nk)] = nv
+			}
+			if err := flattenConfig(name, nested, values); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,27 +17,350 @@ import (
 )
 
 func main() {
+	configFile := flag.String("config", "", "YAML configuration file setting flags and constant labels; command-line flags take precedence")
 	listenAddr := flag.String("listen", ":9835", "Address to listen on for Prometheus metrics")
+	scrapeTimeout := flag.Duration("scrape.timeout", 9*time.Second, "Maximum time each collector may take per scrape; collectors still running are abandoned and reported as failed (0 = no limit)")
+	collectionInterval := flag.Duration("collection.interval", 0, "Collect metrics in the background at this interval and serve the latest snapshot on /metrics (0 = collect on every scrape)")
+	tlsCertFile := flag.String("web.tls-cert-file", "", "PEM certificate chain to serve /metrics over HTTPS with (empty = plain HTTP)")
+	tlsKeyFile := flag.String("web.tls-key-file", "", "PEM private key of -web.tls-cert-file")
+	tlsClientCAFile := flag.String("web.tls-client-ca-file", "", "PEM CA certificates that client certificates must be signed by (empty = no client authentication)")
+	diskInclude := flag.String("disk.device-include", collectors.DefaultDiskDeviceInclude, "Regex of block devices to include in disk I/O metrics (empty = all)")
+	diskExclude := flag.String("disk.device-exclude", collectors.DefaultDiskDeviceExclude, "Regex of block devices to exclude from disk I/O metrics (empty = none)")
+	diskPartitions := flag.Bool("disk.partitions", false, "Report disk I/O for partitions in addition to whole devices, with a parent label naming their disk")
+	fsMountExclude := flag.String("filesystem.mount-exclude", collectors.DefaultFilesystemMountExclude, "Regex of mountpoints to exclude from filesystem metrics")
+	fsTypeExclude := flag.String("filesystem.fstype-exclude", collectors.DefaultFilesystemFSTypeExclude, "Regex of filesystem types to exclude from filesystem metrics")
+	fstrimStampFile := flag.String("fstrim.stamp-file", collectors.DefaultFstrimStampFile, "File whose modification time records the last fstrim run")
+	powerTotalRails := flag.String("power.total-rails", "", "Regex of INA power monitor rails summed into board_power_watts (empty = all rails)")
+	netInclude := flag.String("net.interface-include", "", "Regex of network interfaces to include (empty = all)")
+	netExclude := flag.String("net.interface-exclude", collectors.DefaultNetworkInterfaceExclude, "Regex of network interfaces to exclude (empty = none)")
+	netIDLabel := flag.String("net.id-label", collectors.NetworkIDLabelNone, "Additional stable interface identity label for network metrics: mac, altname, or empty for none")
+	netNetns := flag.Bool("net.netns", false, "Also report interfaces of the named network namespaces in /var/run/netns, with a netns label")
+	netWrapAdjust := flag.Bool("net.counter-wrap-adjust", false, "Correct network traffic counters of drivers that wrap at 32 bits into monotonic 64-bit values")
+	enableKSM := collectorFlag("ksm", false, "Enable the KSM (Kernel Samepage Merging) collector")
+	enableSmartctl := collectorFlag("smartctl", false, "Enable the smartctl-based SMART collector")
+	smartctlPath := flag.String("smartctl.path", "smartctl", "Path to the smartctl binary")
+	enableDeviceMapper := collectorFlag("devicemapper", false, "Enable the device-mapper / LVM collector")
+	vgsPath := flag.String("lvm.vgs-path", "vgs", "Path to the LVM vgs binary")
+	enableZFS := collectorFlag("zfs", false, "Enable the ZFS ARC and pool collector")
+	enableCgroupIO := collectorFlag("cgroup-io", false, "Enable the per-cgroup block I/O collector")
+	cgroupIODepth := flag.Int("cgroup.io-depth", 2, "Maximum cgroup hierarchy depth reported by the cgroup I/O collector")
+	enableContainers := collectorFlag("containers", false, "Enable the per-container (Docker/Podman) resource usage collector")
+	enableKubePods := collectorFlag("kube-pods", false, "Enable the per-pod resource usage collector for Kubernetes nodes")
+	kubeletPodsURL := flag.String("kubelet.pods-url", collectors.DefaultKubeletPodsURL, "Kubelet endpoint listing the pods of the node")
+	kubeletTokenFile := flag.String("kubelet.token-file", "", "File holding a bearer token for the kubelet API (empty = no authentication)")
+	kubeletVerifyTLS := flag.Bool("kubelet.verify-tls", false, "Verify the kubelet serving certificate")
+	kubeletTimeout := flag.Duration("kubelet.timeout", 5*time.Second, "Timeout for the kubelet pod list request")
+	enableRedfish := collectorFlag("redfish", false, "Enable the BMC sensor collector (Redfish)")
+	redfishURL := flag.String("redfish.url", "", "Base URL of the BMC Redfish API, e.g. https://169.254.0.1")
+	redfishUsername := flag.String("redfish.username", "", "BMC user name (empty = no authentication)")
+	redfishPasswordFile := flag.String("redfish.password-file", "", "File holding the BMC password")
+	redfishVerifyTLS := flag.Bool("redfish.verify-tls", false, "Verify the BMC certificate")
+	redfishTimeout := flag.Duration("redfish.timeout", 10*time.Second, "Timeout for a single Redfish request")
+	enableVLANBridge := collectorFlag("vlan-bridge", false, "Enable the VLAN and bridge topology collector")
+	enableTransceiver := collectorFlag("transceiver", false, "Enable the SFP/QSFP transceiver diagnostics collector")
+	enableNftables := collectorFlag("nftables", false, "Enable the nftables rule counter collector")
+	nftPath := flag.String("nftables.nft-path", "nft", "Path to the nft binary")
+	nftInclude := flag.String("nftables.include", "", "Regex of nftables rules (<family>/<table>/<chain>/<comment or handle:N>) and named counters (<family>/<table>/<name>) to export (empty = all)")
+	enableEthtool := collectorFlag("ethtool", false, "Enable the ethtool driver statistics collector")
+	ethtoolStatInclude := flag.String("ethtool.stat-include", "", "Regex of ethtool statistic names to export (empty = all)")
+	enableProbe := collectorFlag("probe", false, "Enable the active ICMP/TCP latency prober")
+	probeICMPTargets := flag.String("probe.icmp-targets", "", "Comma-separated hosts to ping (e.g. the peer Spark's link address)")
+	probeTCPTargets := flag.String("probe.tcp-targets", "", "Comma-separated host:port targets to probe with TCP connects")
+	probeInterval := flag.Duration("probe.interval", 15*time.Second, "Interval between probe rounds")
+	probeTimeout := flag.Duration("probe.timeout", time.Second, "Timeout for a single probe")
+	probeCount := flag.Int("probe.count", 3, "Number of probes sent to each target per round")
+	enableDNSProbe := collectorFlag("dns-probe", false, "Enable the DNS resolution probe")
+	dnsProbeNames := flag.String("dns-probe.names", "", "Comma-separated host names to resolve on every scrape")
+	dnsProbeServer := flag.String("dns-probe.server", "", "DNS server (host:port) to query (empty = system resolver)")
+	dnsProbeTimeout := flag.Duration("dns-probe.timeout", 2*time.Second, "Timeout for a single DNS lookup")
+	enableChrony := collectorFlag("chrony", false, "Enable the chrony NTP tracking collector")
+	chronycPath := flag.String("chrony.chronyc-path", "chronyc", "Path to the chronyc binary")
+	enableSystemd := collectorFlag("systemd", false, "Enable the systemd unit state collector (via D-Bus)")
+	systemdUnits := flag.String("systemd.units", collectors.DefaultSystemdUnits, "Comma-separated systemd units to report the state of")
+	systemdFailedUnitInfo := flag.Bool("systemd.failed-unit-info", false, "Also report every failed systemd unit by name")
+	enableJournal := collectorFlag("journal", false, "Enable the journal error entry collector")
+	journalctlPath := flag.String("journal.journalctl-path", "journalctl", "Path to the journalctl binary")
+	journalIdentifiers := flag.String("journal.identifiers", "", "Comma-separated syslog identifiers to count journal entries of separately; others are counted as \"other\" (empty = no identifier label)")
+	enableFabric := collectorFlag("fabric", false, "Enable the NVIDIA fabric manager and NVLink fabric state collector")
+	enablePeer := collectorFlag("peer", false, "Enable the dual-Spark cluster peer health collector")
+	peerAddress := flag.String("peer.address", "", "Host name or address of the peer Spark, normally on the direct ConnectX link")
+	peerTimeout := flag.Duration("peer.timeout", time.Second, "Timeout for a single ICMP echo request to the peer")
+	peerCount := flag.Int("peer.count", 3, "Number of ICMP echo requests sent to the peer per scrape")
+	federationURL := flag.String("federation.url", "", "Metrics URL of a peer exporter whose metrics are served along with the local ones (empty = disabled)")
+	federationTimeout := flag.Duration("federation.timeout", 5*time.Second, "Timeout for scraping the peer exporter")
+	enableBandwidthProbe := collectorFlag("bandwidth-probe", false, "Enable the periodic TCP bandwidth probe against a peer exporter")
+	bandwidthProbeTarget := flag.String("bandwidth-probe.target", "", "host:port of the peer exporter's bandwidth probe sink")
+	bandwidthProbeInterval := flag.Duration("bandwidth-probe.interval", 15*time.Minute, "Interval between bandwidth probes")
+	bandwidthProbeDuration := flag.Duration("bandwidth-probe.duration", 5*time.Second, "Duration of a single bandwidth probe")
+	bandwidthProbeStreams := flag.Int("bandwidth-probe.streams", 4, "Number of parallel TCP streams per bandwidth probe")
+	bandwidthProbeListen := flag.String("bandwidth-probe.listen", "", "Address to accept bandwidth probes from a peer exporter on, e.g. :9836 (empty = disabled)")
+	enableInference := collectorFlag("inference", false, "Enable the local LLM inference server probe (Ollama, vLLM, TGI)")
+	inferenceServers := flag.String("inference.servers", collectors.DefaultInferenceServers, "Comma-separated type=URL inference servers to probe; type is ollama, vllm, tgi, or openai")
+	inferenceTimeout := flag.Duration("inference.timeout", 2*time.Second, "Timeout for a single inference server request")
+	enableTriton := collectorFlag("triton", false, "Enable re-exposing the metrics of a co-located Triton Inference Server")
+	tritonURL := flag.String("triton.url", collectors.DefaultTritonURL, "Metrics endpoint of the Triton Inference Server")
+	tritonInclude := flag.String("triton.include", "^nv_", "Regex of Triton metric names to re-expose (empty = all)")
+	tritonTimeout := flag.Duration("triton.timeout", 5*time.Second, "Timeout for scraping the Triton metrics endpoint")
+	enableDockerHealth := collectorFlag("docker-health", false, "Enable the Docker and NVIDIA container runtime health check")
+	dockerSocket := flag.String("docker.socket", collectors.DefaultDockerSocket, "Path to the Docker daemon API socket")
+	nvidiaContainerCLIPath := flag.String("docker.nvidia-container-cli-path", "nvidia-container-cli", "Path to the nvidia-container-cli binary")
+	enableContainerImages := collectorFlag("container-images", false, "Enable the locally pulled container image inventory collector")
+	containerImagesInclude := flag.String("container-images.include", collectors.DefaultContainerImageInclude, "Regex of image repositories to report (empty = all)")
+	containerImagesMax := flag.Int("container-images.max", 100, "Maximum number of images reported individually, largest first")
+	enableNVMeoF := collectorFlag("nvmeof", false, "Enable the NVMe over Fabrics initiator and target collector")
+
+	// Collectors enabled by default, in registration order. Constructors run after
+	// flag parsing, so they may use the other flags.
+	var netIncludeRe, netExcludeRe *regexp.Regexp
+	defaultCollectors := []struct {
+		name, help string
+		new        func() prometheus.Collector
+	}{
+		{"cpu", "Enable the CPU usage and frequency collector", func() prometheus.Collector { return collectors.NewCPUCollector() }},
+		{"thermal", "Enable the thermal zone collector", func() prometheus.Collector { return collectors.NewThermalCollector() }},
+		{"gpu", "Enable the GPU (nvidia-smi) collector", func() prometheus.Collector { return collectors.NewGPUCollector() }},
+		{"nvidia-software", "Enable the NVIDIA software version collector", func() prometheus.Collector { return collectors.NewNVIDIASoftwareCollector() }},
+		{"cuda-toolkit", "Enable the CUDA toolkit inventory collector", func() prometheus.Collector { return collectors.NewCUDAToolkitCollector() }},
+		{"display", "Enable the display mode collector", func() prometheus.Collector { return collectors.NewDisplayCollector() }},
+		{"memory", "Enable the memory collector", func() prometheus.Collector { return collectors.NewMemoryCollector() }},
+		{"buddyinfo", "Enable the memory fragmentation (buddyinfo) collector", func() prometheus.Collector { return collectors.NewBuddyInfoCollector() }},
+		{"emc", "Enable the memory controller (EMC) frequency collector", func() prometheus.Collector { return collectors.NewEMCCollector() }},
+		{"uptime", "Enable the uptime collector", func() prometheus.Collector { return collectors.NewUptimeCollector() }},
+		{"boottime", "Enable the boot stage duration collector", func() prometheus.Collector { return collectors.NewBootTimeCollector() }},
+		{"os-info", "Enable the OS version collector", func() prometheus.Collector { return collectors.NewOSInfoCollector() }},
+		{"apt", "Enable the APT pending updates collector", func() prometheus.Collector { return collectors.NewAptCollector() }},
+		{"dmi", "Enable the DMI hardware identity collector", func() prometheus.Collector { return collectors.NewDMICollector() }},
+		{"firmware", "Enable the firmware version collector", func() prometheus.Collector { return collectors.NewFirmwareCollector() }},
+		{"watchdog", "Enable the hardware watchdog collector", func() prometheus.Collector { return collectors.NewWatchdogCollector() }},
+		{"entropy", "Enable the kernel entropy pool collector", func() prometheus.Collector { return collectors.NewEntropyCollector() }},
+		{"filefd", "Enable the file descriptor collector", func() prometheus.Collector { return collectors.NewFileFDCollector() }},
+		{"logins", "Enable the login session collector", func() prometheus.Collector { return collectors.NewLoginsCollector() }},
+		{"processes", "Enable the process state collector", func() prometheus.Collector { return collectors.NewProcessStateCollector() }},
+		{"timex", "Enable the kernel clock synchronization (adjtimex) collector", func() prometheus.Collector { return collectors.NewTimexCollector() }},
+		{"hardware-errors", "Enable the hardware error (EDAC, AER, MCE) collector", func() prometheus.Collector { return collectors.NewHardwareErrorsCollector() }},
+		{"taint", "Enable the kernel taint collector", func() prometheus.Collector { return collectors.NewKernelTaintCollector() }},
+		{"lsm", "Enable the Linux security module collector", func() prometheus.Collector { return collectors.NewSecurityModuleCollector() }},
+		{"coredump", "Enable the core dump collector", func() prometheus.Collector { return collectors.NewCoredumpCollector() }},
+		{"pstore", "Enable the pstore crash record collector", func() prometheus.Collector { return collectors.NewPstoreCollector() }},
+		{"disk", "Enable the disk I/O collector", func() prometheus.Collector {
+			return collectors.NewDiskCollector(
+				mustCompileFlag("disk.device-include", *diskInclude),
+				mustCompileFlag("disk.device-exclude", *diskExclude),
+				*diskPartitions,
+			)
+		}},
+		{"nvme", "Enable the NVMe SMART collector", func() prometheus.Collector { return collectors.NewNVMeCollector() }},
+		{"drivetemp", "Enable the drive temperature collector", func() prometheus.Collector { return collectors.NewDriveTempCollector() }},
+		{"hwmon", "Enable the hwmon sensor collector", func() prometheus.Collector { return collectors.NewHwmonCollector() }},
+		{"power-supply", "Enable the power supply collector", func() prometheus.Collector { return collectors.NewPowerSupplyCollector() }},
+		{"power-rail", "Enable the power rail collector", func() prometheus.Collector {
+			return collectors.NewPowerRailCollector(mustCompileFlag("power.total-rails", *powerTotalRails))
+		}},
+		{"power-mode", "Enable the power mode collector", func() prometheus.Collector { return collectors.NewPowerModeCollector() }},
+		{"clock-lock", "Enable the clock lock collector", func() prometheus.Collector { return collectors.NewClockLockCollector() }},
+		{"mdstat", "Enable the software RAID (mdstat) collector", func() prometheus.Collector { return collectors.NewMDStatCollector() }},
+		{"btrfs", "Enable the Btrfs collector", func() prometheus.Collector { return collectors.NewBtrfsCollector() }},
+		{"filesystem", "Enable the filesystem collector", func() prometheus.Collector {
+			return collectors.NewFilesystemCollector(
+				mustCompileFlag("filesystem.mount-exclude", *fsMountExclude),
+				mustCompileFlag("filesystem.fstype-exclude", *fsTypeExclude),
+			)
+		}},
+		{"fstrim", "Enable the fstrim collector", func() prometheus.Collector { return collectors.NewFstrimCollector(*fstrimStampFile) }},
+		{"filesystem-errors", "Enable the filesystem error collector", func() prometheus.Collector { return collectors.NewFilesystemErrorsCollector() }},
+		{"netstat", "Enable the network protocol statistics (netstat) collector", func() prometheus.Collector { return collectors.NewNetstatCollector() }},
+		{"sockstat", "Enable the socket statistics (sockstat) collector", func() prometheus.Collector { return collectors.NewSockstatCollector() }},
+		{"conntrack", "Enable the connection tracking collector", func() prometheus.Collector { return collectors.NewConntrackCollector() }},
+		{"neighbor", "Enable the ARP/neighbor table collector", func() prometheus.Collector { return collectors.NewNeighborCollector() }},
+		{"softnet", "Enable the softnet collector", func() prometheus.Collector { return collectors.NewSoftnetCollector() }},
+		{"network", "Enable the network interface collector", func() prometheus.Collector {
+			return collectors.NewNetworkCollector(netIncludeRe, netExcludeRe, *netIDLabel, *netNetns, *netWrapAdjust)
+		}},
+		{"wifi", "Enable the Wi-Fi collector", func() prometheus.Collector { return collectors.NewWifiCollector() }},
+		{"infiniband", "Enable the InfiniBand/RDMA collector", func() prometheus.Collector { return collectors.NewInfinibandCollector() }},
+		{"bonding", "Enable the bonding collector", func() prometheus.Collector { return collectors.NewBondingCollector() }},
+		{"pfc", "Enable the priority flow control collector", func() prometheus.Collector { return collectors.NewPFCCollector(netIncludeRe, netExcludeRe) }},
+	}
+	defaultSwitches := make([]collectorSwitch, len(defaultCollectors))
+	for i, c := range defaultCollectors {
+		defaultSwitches[i] = collectorFlag(c.name, true, c.help)
+	}
+
 	flag.Parse()
 
+	constLabels := make(prometheus.Labels)
+	if *configFile != "" {
+		labels, err := loadConfig(flag.CommandLine, *configFile)
+		if err != nil {
+			log.Fatalf("failed to load configuration: %v", err)
+		}
+		for name, value := range labels {
+			if name == "host" || !labelNameRe.MatchString(name) {
+				log.Fatalf("invalid label name in configuration: %q", name)
+			}
+			constLabels[name] = value
+		}
+	}
+
 	// Resolve hostname for global "host" label
 	hostname, err := os.Hostname()
 	if err != nil {
 		log.Fatalf("failed to get hostname: %v", err)
 	}
+	constLabels["host"] = hostname
 
-	// Wrap the default registerer to add "host" label to all metrics
+	// Wrap the default registerer to add "host" and the configured labels to all metrics
 	registry := prometheus.WrapRegistererWith(
-		prometheus.Labels{"host": hostname},
+		constLabels,
 		prometheus.DefaultRegisterer,
 	)
 
-	// Register all collectors
-	registry.MustRegister(collectors.NewCPUCollector())
-	registry.MustRegister(collectors.NewGPUCollector())
-	registry.MustRegister(collectors.NewMemoryCollector())
-	registry.MustRegister(collectors.NewDiskCollector())
-	registry.MustRegister(collectors.NewNetworkCollector())
+	// Register every collector instrumented with its collection duration and success,
+	// bounded by the scrape timeout
+	register := func(name string, c prometheus.Collector) {
+		registry.MustRegister(collectors.NewInstrumentedCollector(name, c, *scrapeTimeout))
+	}
+
+	// Register collectors enabled by default
+	netIncludeRe = mustCompileFlag("net.interface-include", *netInclude)
+	netExcludeRe = mustCompileFlag("net.interface-exclude", *netExclude)
+	switch *netIDLabel {
+	case collectors.NetworkIDLabelNone, collectors.NetworkIDLabelMAC, collectors.NetworkIDLabelAltName:
+	default:
+		log.Fatalf("invalid value for -net.id-label: %q (want mac, altname, or empty)", *netIDLabel)
+	}
+	for i, c := range defaultCollectors {
+		if defaultSwitches[i].enabled() {
+			register(c.name, c.new())
+		}
+	}
+
+	// Register opt-in collectors
+	if enableKSM.enabled() {
+		register("ksm", collectors.NewKSMCollector())
+	}
+	if enableSmartctl.enabled() {
+		register("smartctl", collectors.NewSmartctlCollector(*smartctlPath))
+	}
+	if enableDeviceMapper.enabled() {
+		register("devicemapper", collectors.NewDeviceMapperCollector(*vgsPath))
+	}
+	if enableZFS.enabled() {
+		register("zfs", collectors.NewZFSCollector())
+	}
+	if enableCgroupIO.enabled() {
+		register("cgroup-io", collectors.NewCgroupIOCollector(*cgroupIODepth))
+	}
+	if enableContainers.enabled() {
+		register("containers", collectors.NewContainerCollector())
+	}
+	if enableKubePods.enabled() {
+		register("kube-pods", collectors.NewKubePodCollector(*kubeletPodsURL, *kubeletTokenFile, *kubeletVerifyTLS, *kubeletTimeout))
+	}
+	if enableRedfish.enabled() {
+		if *redfishURL == "" {
+			log.Fatalf("-collector.redfish requires -redfish.url")
+		}
+		register("redfish", collectors.NewRedfishCollector(*redfishURL, *redfishUsername, *redfishPasswordFile, *redfishVerifyTLS, *redfishTimeout))
+	}
+	if enableVLANBridge.enabled() {
+		register("vlan-bridge", collectors.NewVLANBridgeCollector(netIncludeRe, netExcludeRe))
+	}
+	if enableTransceiver.enabled() {
+		register("transceiver", collectors.NewTransceiverCollector(netIncludeRe, netExcludeRe))
+	}
+	if enableNftables.enabled() {
+		register("nftables", collectors.NewNftablesCollector(*nftPath, mustCompileFlag("nftables.include", *nftInclude)))
+	}
+	if enableEthtool.enabled() {
+		register("ethtool", collectors.NewEthtoolCollector(
+			netIncludeRe,
+			netExcludeRe,
+			mustCompileFlag("ethtool.stat-include", *ethtoolStatInclude),
+		))
+	}
+	if enableProbe.enabled() {
+		if *probeCount < 1 {
+			log.Fatalf("invalid value for -probe.count: %d (want at least 1)", *probeCount)
+		}
+		register("probe", collectors.NewProbeCollector(
+			splitList(*probeICMPTargets),
+			splitList(*probeTCPTargets),
+			*probeInterval,
+			*probeTimeout,
+			*probeCount,
+		))
+	}
+	if enableDNSProbe.enabled() {
+		register("dns-probe", collectors.NewDNSProbeCollector(splitList(*dnsProbeNames), *dnsProbeServer, *dnsProbeTimeout))
+	}
+	if enableChrony.enabled() {
+		register("chrony", collectors.NewChronyCollector(*chronycPath))
+	}
+	if enableSystemd.enabled() {
+		register("systemd", collectors.NewSystemdCollector(splitList(*systemdUnits), *systemdFailedUnitInfo))
+	}
+	if enableJournal.enabled() {
+		register("journal", collectors.NewJournalCollector(*journalctlPath, splitList(*journalIdentifiers)))
+	}
+	if enableFabric.enabled() {
+		register("fabric", collectors.NewFabricCollector())
+	}
+	if enablePeer.enabled() {
+		if *peerAddress == "" {
+			log.Fatalf("-collector.peer requires -peer.address")
+		}
+		if *peerCount < 1 {
+			log.Fatalf("invalid value for -peer.count: %d (want at least 1)", *peerCount)
+		}
+		register("peer", collectors.NewPeerCollector(*peerAddress, *peerTimeout, *peerCount))
+	}
+	if enableBandwidthProbe.enabled() {
+		if *bandwidthProbeTarget == "" {
+			log.Fatalf("-collector.bandwidth-probe requires -bandwidth-probe.target")
+		}
+		if *bandwidthProbeStreams < 1 {
+			log.Fatalf("invalid value for -bandwidth-probe.streams: %d (want at least 1)", *bandwidthProbeStreams)
+		}
+		register("bandwidth-probe", collectors.NewBandwidthProbeCollector(*bandwidthProbeTarget, *bandwidthProbeInterval, *bandwidthProbeDuration, *bandwidthProbeStreams))
+	}
+	if enableInference.enabled() {
+		var servers []collectors.InferenceServer
+		for _, item := range splitList(*inferenceServers) {
+			typ, url, ok := strings.Cut(item, "=")
+			switch typ {
+			case collectors.InferenceServerOllama, collectors.InferenceServerVLLM, collectors.InferenceServerTGI, collectors.InferenceServerOpenAI:
+			default:
+				ok = false
+			}
+			if !ok || url == "" {
+				log.Fatalf("invalid value for -inference.servers: %q (want type=URL with type ollama, vllm, tgi, or openai)", item)
+			}
+			servers = append(servers, collectors.InferenceServer{Type: typ, URL: url})
+		}
+		register("inference", collectors.NewInferenceCollector(servers, *inferenceTimeout))
+	}
+	if enableTriton.enabled() {
+		register("triton", collectors.NewTritonCollector(*tritonURL, mustCompileFlag("triton.include", *tritonInclude), *tritonTimeout))
+	}
+	if enableDockerHealth.enabled() {
+		register("docker-health", collectors.NewDockerHealthCollector(*dockerSocket, *nvidiaContainerCLIPath))
+	}
+	if enableContainerImages.enabled() {
+		register("container-images", collectors.NewContainerImageCollector(
+			*dockerSocket,
+			mustCompileFlag("container-images.include", *containerImagesInclude),
+			*containerImagesMax,
+		))
+	}
+	if enableNVMeoF.enabled() {
+		register("nvmeof", collectors.NewNVMeoFCollector())
+	}
+	if *bandwidthProbeListen != "" {
+		go func() {
+			log.Fatal(collectors.ListenBandwidthProbe(*bandwidthProbeListen))
+		}()
+	}
 
 	// Landing page
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -54,8 +380,96 @@ func main() {
 	})
 
 	// Prometheus metrics endpoint
-	http.Handle("/metrics", promhttp.Handler())
+	var gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+	if *collectionInterval > 0 {
+		gatherer = collectors.NewCachingGatherer(prometheus.DefaultGatherer, *collectionInterval)
+	}
+	metricsHandler := promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	if *federationURL != "" {
+		// Serve the peer's metrics next to the local ones. A failed peer scrape
+		// is reported by federation_up and must not fail the local scrape.
+		federated := promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(
+			prometheus.Gatherers{
+				gatherer,
+				collectors.NewFederationGatherer(*federationURL, hostname, *federationTimeout),
+			},
+			promhttp.HandlerOpts{ErrorLog: log.Default(), ErrorHandling: promhttp.ContinueOnError},
+		))
+		local := metricsHandler
+		metricsHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(collectors.FederationHeader) != "" {
+				local.ServeHTTP(w, r)
+				return
+			}
+			federated.ServeHTTP(w, r)
+		})
+	}
+	http.Handle("/metrics", metricsHandler)
 
-	log.Printf("DGX Spark Prometheus Exporter listening on %s", *listenAddr)
-	log.Fatal(http.ListenAndServe(*listenAddr, nil))
+	if *tlsCertFile == "" && *tlsKeyFile == "" {
+		if *tlsClientCAFile != "" {
+			log.Fatalf("-web.tls-client-ca-file requires -web.tls-cert-file and -web.tls-key-file")
+		}
+		log.Printf("DGX Spark Prometheus Exporter listening on %s", *listenAddr)
+		log.Fatal(http.ListenAndServe(*listenAddr, nil))
+	}
+	if *tlsCertFile == "" || *tlsKeyFile == "" {
+		log.Fatalf("-web.tls-cert-file and -web.tls-key-file must be set together")
+	}
+	tlsConfig, err := newTLSConfig(*tlsCertFile, *tlsKeyFile, *tlsClientCAFile)
+	if err != nil {
+		log.Fatalf("failed to load TLS configuration: %v", err)
+	}
+	server := &http.Server{Addr: *listenAddr, TLSConfig: tlsConfig}
+	log.Printf("DGX Spark Prometheus Exporter listening on %s (HTTPS)", *listenAddr)
+	log.Fatal(server.ListenAndServeTLS("", ""))
+}
+
+// collectorSwitch holds the -collector.<name> and -no-collector.<name> flags that
+// enable and disable a collector.
+type collectorSwitch struct {
+	enable, disable *bool
+}
+
+// collectorFlag defines the switch flags of the named collector. Either flag can
+// turn off a collector enabled by default (-no-collector.gpu or
+// -collector.gpu=false); -no-collector.<name> takes precedence.
+func collectorFlag(name string, enabledByDefault bool, help string) collectorSwitch {
+	return collectorSwitch{
+		enable:  flag.Bool("collector."+name, enabledByDefault, help),
+		disable: flag.Bool("no-collector."+name, false, "Disable the "+name+" collector"),
+	}
+}
+
+// enabled reports whether the collector should be registered.
+func (s collectorSwitch) enabled() bool {
+	return *s.enable && !*s.disable
+}
+
+// labelNameRe matches valid Prometheus label names.
+var labelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// mustCompileFlag compiles the regular expression passed in the named flag,
+// exiting with an error message if it is invalid. An empty expression yields nil,
+// which collectors treat as "no filter".
+func mustCompileFlag(name, expr string) *regexp.Regexp {
+	if expr == "" {
+		return nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Fatalf("invalid regular expression for -%s: %v", name, err)
+	}
+	return re
+}
+
+// splitList splits a comma-separated flag value into its non-empty, trimmed elements.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
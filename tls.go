@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// newTLSConfig returns the TLS configuration of the metrics endpoint serving the
+// certificate chain and key in the PEM files certFile and keyFile. If clientCAFile
+// is set, clients must present a certificate signed by one of its CAs.
+func newTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	certs := &certReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := certs.getCertificate(nil); err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.getCertificate,
+	}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no PEM certificates found", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// certReloader loads a certificate and key pair, loading it again when either
+// file is modified so renewed certificates are served without a restart.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// getCertificate returns the current certificate, as tls.Config.GetCertificate.
+// If a modified pair cannot be loaded, for example while only one of the files
+// has been replaced, the previous certificate is kept.
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	var modTime time.Time
+	for _, path := range []string{r.certFile, r.keyFile} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return r.current(err)
+		}
+		if fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert, r.modTime = &cert, modTime
+	return r.cert, nil
+}
+
+// current returns the certificate loaded last, or err if there is none.
+func (r *certReloader) current(err error) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert == nil {
+		return nil, err
+	}
+	return r.cert, nil
+}